@@ -1,6 +1,7 @@
 package benchmarks
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
@@ -63,7 +64,7 @@ func main() {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		fmt.Println(res.Output)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
@@ -101,7 +102,7 @@ func main() {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
 		}
@@ -138,7 +139,7 @@ func main() {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
 		}
@@ -161,7 +162,7 @@ func BenchmarkPythonHelloWorld(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
 		}
@@ -190,7 +191,7 @@ print(fib(20))`
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
 		}
@@ -221,7 +222,7 @@ else:
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		res, err := runner.Run(req)
+		res, err := runner.Run(context.Background(), req)
 		if err != nil {
 			b.Fatalf("Run failed: %v", err)
 		}
@@ -9,9 +9,16 @@ import (
 
 	"github.com/cutekitek/rankode-runner/internal/config"
 	"github.com/cutekitek/rankode-runner/internal/files"
+	"github.com/cutekitek/rankode-runner/internal/grpcapi"
+	"github.com/cutekitek/rankode-runner/internal/httpapi"
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/natsjs"
+	"github.com/cutekitek/rankode-runner/internal/problems"
 	"github.com/cutekitek/rankode-runner/internal/rabbitmq"
+	"github.com/cutekitek/rankode-runner/internal/redisstream"
 
 	"github.com/cutekitek/rankode-runner/internal/runner/sandbox"
+	"github.com/cutekitek/rankode-runner/internal/worker"
 )
 
 func panicErr(err error) {
@@ -35,6 +42,77 @@ func setLogLevel(level string) {
 	}
 }
 
+type transport interface {
+	Start() error
+	Close()
+}
+
+// connectedSource is a job source which has to be connected before use
+type connectedSource interface {
+	worker.JobSource
+	worker.ResultSink
+	Start() error
+	Close()
+}
+
+// poolTransport runs jobs from a connected source with the shared worker pool
+type poolTransport struct {
+	source connectedSource
+	pool   *worker.Pool
+}
+
+func newPoolTransport(source connectedSource, workers int, attemptJudge *judge.Judge) *poolTransport {
+	return &poolTransport{source: source, pool: worker.NewPool(workers, attemptJudge, source, source)}
+}
+
+func (t *poolTransport) Start() error {
+	if err := t.source.Start(); err != nil {
+		return err
+	}
+	t.pool.Start()
+	return nil
+}
+
+func (t *poolTransport) Close() {
+	t.pool.Close()
+	t.source.Close()
+}
+
+func newRabbitMQTransport(cfg *config.Config, attemptJudge *judge.Judge) transport {
+	queues := make([]rabbitmq.QueueConfig, 0, len(cfg.RabbitMQQueues))
+	for _, q := range cfg.RabbitMQQueues {
+		queue, err := rabbitmq.ParseQueueConfig(q)
+		panicErr(err)
+		queues = append(queues, queue)
+	}
+	listener, err := rabbitmq.NewRabbitMQHandler(rabbitmq.RabbitMqHandlerConfig{
+		Login:          cfg.RabbitMQUser,
+		Password:       cfg.RabbitMQPassword,
+		Host:           cfg.RabbitMQHost,
+		Port:           cfg.RabbitMQPort,
+		WorkersCount:   cfg.WorkersCount,
+		Queues:         queues,
+		MaxPriority:    cfg.RabbitMQMaxPriority,
+		ResponseQueue:  cfg.RabbitMQResponseQueue,
+		EventsExchange: cfg.RabbitMQEventsExchange,
+		CancelExchange: cfg.RabbitMQCancelExchange,
+	})
+	panicErr(err)
+	return newPoolTransport(listener, cfg.WorkersCount, attemptJudge)
+}
+
+func newFileStorage(cfg *config.Config) (judge.FileStorage, error) {
+	if cfg.StorageBackend == config.StorageFS {
+		return files.NewLocalStorage(cfg.StoragePath)
+	}
+	return files.NewFileStorage(files.Config{
+		Endpoint:  cfg.S3Endpoint,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+		Bucket:    cfg.S3Bucket,
+	})
+}
+
 func main() {
 	cfg, err := config.NewConfig()
 	panicErr(err)
@@ -46,29 +124,80 @@ func main() {
 
 	panicErr(runner.Init())
 	panicErr(err)
-	fileStorage := files.NewFileStorage(files.Config{
-		Endpoint:  cfg.S3Endpoint,
-		AccessKey: cfg.S3AccessKey,
-		SecretKey: cfg.S3SecretKey,
-		Bucket:    cfg.S3Bucket,
-	})
-	listener, err := rabbitmq.NewRabbitMQHandler(rabbitmq.RabbitMqHandlerConfig{
-		Login:        cfg.RabbitMQUser,
-		Password:     cfg.RabbitMQPassword,
-		Host:         cfg.RabbitMQHost,
-		Port:         cfg.RabbitMQPort,
-		WorkersCount: cfg.WorkersCount,
-	}, runner, fileStorage)
-	if err != nil {
-		panicErr(err)
+	fileStorage, err := newFileStorage(cfg)
+	panicErr(err)
+	packages, err := problems.NewCache(problems.CacheConfig{Dir: cfg.PackagesDir, MaxSize: cfg.PackageMaxSize}, fileStorage)
+	panicErr(err)
+	attemptJudge := judge.NewJudge(judge.Config{
+		AttemptTimeout:     cfg.AttemptTimeout,
+		MaxInlineSize:      cfg.MaxInlineSize,
+		DefaultMemoryLimit: cfg.DefaultMemoryLimit,
+		MinMemoryLimit:     cfg.MinMemoryLimit,
+		Workers:            cfg.WorkersCount,
+	}, runner, fileStorage, packages)
+
+	var transports []transport
+	for _, name := range cfg.Transports {
+		switch name {
+		case config.TransportRabbitMQ:
+			transports = append(transports, newRabbitMQTransport(cfg, attemptJudge))
+		case config.TransportNATS:
+			transports = append(transports, newPoolTransport(natsjs.NewSource(natsjs.Config{
+				URL:             cfg.NATSURL,
+				Stream:          cfg.NATSStream,
+				Subject:         cfg.NATSSubject,
+				Durable:         cfg.NATSDurable,
+				ResponseSubject: cfg.NATSResponseSubject,
+				EventsSubject:   cfg.NATSEventsSubject,
+				CancelSubject:   cfg.NATSCancelSubject,
+				AckWait:         cfg.NATSAckWait,
+				MaxDeliver:      cfg.NATSMaxDeliver,
+			}), cfg.WorkersCount, attemptJudge))
+		case config.TransportRedis:
+			transports = append(transports, newPoolTransport(redisstream.NewSource(redisstream.Config{
+				Addr:           cfg.RedisAddr,
+				Password:       cfg.RedisPassword,
+				DB:             cfg.RedisDB,
+				Stream:         cfg.RedisStream,
+				Group:          cfg.RedisGroup,
+				Consumer:       cfg.RedisConsumer,
+				ResponseStream: cfg.RedisResponseStream,
+				ResponseMaxLen: cfg.RedisResponseMaxLen,
+				EventsChannel:  cfg.RedisEventsChannel,
+				CancelChannel:  cfg.RedisCancelChannel,
+				ClaimIdle:      cfg.RedisClaimIdle,
+				MaxDeliver:     cfg.RedisMaxDeliver,
+			}), cfg.WorkersCount, attemptJudge))
+		case config.TransportHTTP:
+			transports = append(transports, httpapi.NewServer(httpapi.Config{
+				Addr:       cfg.HTTPAddr,
+				MaxPending: cfg.MaxPendingAttempts,
+				MaxResults: cfg.MaxStoredResults,
+			}, attemptJudge))
+		case config.TransportGRPC:
+			transports = append(transports, grpcapi.NewServer(grpcapi.Config{
+				Addr:       cfg.GRPCAddr,
+				MaxPending: cfg.MaxPendingAttempts,
+				MaxResults: cfg.MaxStoredResults,
+			}, attemptJudge))
+		}
 	}
-	slog.Info("app started")
-	if err := listener.Start(); err != nil {
-		panicErr(err)
+	for _, t := range transports {
+		panicErr(t.Start())
 	}
+	slog.Info("app started", "transports", cfg.Transports)
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	<-stop
-	listener.Close()
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range stop {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := runner.Reload(); err != nil {
+			slog.Error("failed to reload languages", "error", err)
+		}
+	}
+	for _, t := range transports {
+		t.Close()
+	}
 	runner.Close()
 }
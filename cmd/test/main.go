@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -17,7 +18,7 @@ func main() {
 	if err := runner.Init(); err != nil {
 		panic(err)
 	}
-	res, err := runner.Run(&dto.RunRequest{
+	res, err := runner.Run(context.Background(), &dto.RunRequest{
 		Image: "sh",
 		Code: command,
 		Input: []string{""},
@@ -35,7 +35,11 @@ for lang in $target_langs; do
             APK_PACKAGES="$APK_PACKAGES openjdk21"
             ;;
         python|py|python3)
-            APK_PACKAGES="$APK_PACKAGES python3"
+            # Pinned to the version in languages/python3/config.json
+            APK_PACKAGES="$APK_PACKAGES python3~3.12"
+            ;;
+        pypy|pypy3)
+            APK_PACKAGES="$APK_PACKAGES pypy3"
             ;;
         perl)
             APK_PACKAGES="$APK_PACKAGES perl"
@@ -44,10 +48,13 @@ for lang in $target_langs; do
             APK_PACKAGES="$APK_PACKAGES dotnet-sdk icu-libs"
             ;;
         rust|rustlang)
+            # rustc links with the system C toolchain. The toolchain is pinned to the
+            # version in languages/rust/config.json
+            APK_PACKAGES="$APK_PACKAGES gcc musl-dev"
             export RUSTUP_HOME=/usr/local/rustup
             export CARGO_HOME=/usr/local/cargo
             
-            curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y --profile minimal --no-modify-path
+            curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y --profile minimal --default-toolchain 1.90.0 --no-modify-path
             
             echo "Copying Rust binaries to /usr/bin..."
             cp $CARGO_HOME/bin/rustc /usr/bin/rustc
@@ -58,6 +65,21 @@ for lang in $target_langs; do
             
             echo "Rust installed and copied to /usr/bin."
             ;;
+        kotlin|kt)
+            APK_PACKAGES="$APK_PACKAGES openjdk21 bash"
+            # The archive is checked against a pinned checksum, see KOTLIN_SHA256 in the Dockerfile
+            KOTLIN_VERSION=${KOTLIN_VERSION:-2.2.20}
+            if [ -z "$KOTLIN_SHA256" ]; then
+                echo "KOTLIN_SHA256 is not set: pass the sha256 of kotlin-compiler-$KOTLIN_VERSION.zip from its release page."
+                exit 1
+            fi
+            curl -fsSL -o /tmp/kotlinc.zip https://github.com/JetBrains/kotlin/releases/download/v$KOTLIN_VERSION/kotlin-compiler-$KOTLIN_VERSION.zip
+            echo "$KOTLIN_SHA256  /tmp/kotlinc.zip" | sha256sum -c -
+            unzip -q /tmp/kotlinc.zip -d /usr/lib
+            rm /tmp/kotlinc.zip
+            ln -sf /usr/lib/kotlinc/bin/kotlinc /usr/bin/kotlinc
+            echo "Kotlin compiler installed to /usr/lib/kotlinc."
+            ;;
         javascript|js|bun)
             APK_PACKAGES="$APK_PACKAGES gcompat libstdc++"
             curl -fsSL https://bun.sh/install | bash
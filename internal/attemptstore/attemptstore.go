@@ -0,0 +1,149 @@
+// Package attemptstore keeps attempts of the HTTP and gRPC transports by id until
+// their results are fetched or expire.
+package attemptstore
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxPending  = 256
+	defaultMaxFinished = 1000
+	defaultTTL         = time.Hour
+)
+
+var (
+	ErrFull    = errors.New("too many pending attempts")
+	ErrRunning = errors.New("attempt is already running")
+)
+
+type Config struct {
+	// Max attempts added or acquired and not finished
+	MaxPending int
+	// Max finished attempts kept, the oldest are removed first
+	MaxFinished int
+	// How long finished attempts are kept
+	TTL time.Duration
+}
+
+type entry[T any] struct {
+	id       int64
+	value    T
+	finished time.Time
+	// position in the finished list, nil while the attempt runs
+	elem *list.Element
+}
+
+// Store keeps values of attempts by id. Running attempts are never removed, so
+// their number is limited by MaxPending instead.
+type Store[T any] struct {
+	cfg Config
+
+	mu      sync.Mutex
+	entries map[int64]*entry[T]
+	// finished entries, the oldest first
+	finished *list.List
+	pending  int
+	lastId   int64
+}
+
+func New[T any](cfg Config) *Store[T] {
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = defaultMaxPending
+	}
+	if cfg.MaxFinished <= 0 {
+		cfg.MaxFinished = defaultMaxFinished
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	return &Store[T]{cfg: cfg, entries: make(map[int64]*entry[T]), finished: list.New()}
+}
+
+// Add stores value of a running attempt and returns its id, a new one if id is
+// zero. It fails with ErrFull if there are too many pending attempts and with
+// ErrRunning if an attempt with the id is not finished yet.
+func (s *Store[T]) Add(id int64, value T) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict()
+	if s.pending >= s.cfg.MaxPending {
+		return 0, ErrFull
+	}
+	if id == 0 {
+		for s.entries[s.lastId+1] != nil {
+			s.lastId++
+		}
+		s.lastId++
+		id = s.lastId
+	} else if e, ok := s.entries[id]; ok {
+		if e.elem == nil {
+			return 0, ErrRunning
+		}
+		s.finished.Remove(e.elem)
+	}
+	s.entries[id] = &entry[T]{id: id, value: value}
+	s.pending++
+	return id, nil
+}
+
+// Finish replaces value of the running attempt with its result and frees its
+// pending place
+func (s *Store[T]) Finish(id int64, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || e.elem != nil {
+		return
+	}
+	e.value = value
+	e.finished = time.Now()
+	e.elem = s.finished.PushBack(e)
+	s.pending--
+	s.evict()
+}
+
+// Get returns value of the attempt and whether it is finished
+func (s *Store[T]) Get(id int64) (value T, finished bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return value, false, false
+	}
+	return e.value, e.elem != nil, true
+}
+
+// Acquire takes a pending place for an attempt that is not stored, it fails with
+// ErrFull if there are too many pending attempts. The place is freed by Release.
+func (s *Store[T]) Acquire() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending >= s.cfg.MaxPending {
+		return ErrFull
+	}
+	s.pending++
+	return nil
+}
+
+func (s *Store[T]) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+}
+
+// evict removes expired and excess finished attempts, must be called with mu locked
+func (s *Store[T]) evict() {
+	now := time.Now()
+	for front := s.finished.Front(); front != nil; front = s.finished.Front() {
+		e := front.Value.(*entry[T])
+		if s.finished.Len() <= s.cfg.MaxFinished && now.Sub(e.finished) <= s.cfg.TTL {
+			break
+		}
+		s.finished.Remove(front)
+		delete(s.entries, e.id)
+	}
+}
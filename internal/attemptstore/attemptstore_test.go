@@ -0,0 +1,63 @@
+package attemptstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore_Pending(t *testing.T) {
+	s := New[string](Config{MaxPending: 2})
+	id, err := s.Add(0, "running")
+	if err != nil || id != 1 {
+		t.Fatalf("Unexpected id %d, error: %v", id, err)
+	}
+	if _, err := s.Add(1, "again"); !errors.Is(err, ErrRunning) {
+		t.Errorf("Running attempt must not be replaced, got %v", err)
+	}
+	if err := s.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add(0, "third"); !errors.Is(err, ErrFull) {
+		t.Errorf("Attempt beyond MaxPending must be rejected, got %v", err)
+	}
+	s.Release()
+	s.Finish(1, "done")
+	if value, finished, ok := s.Get(1); !ok || !finished || value != "done" {
+		t.Errorf("Unexpected attempt: %q, finished: %v, ok: %v", value, finished, ok)
+	}
+	if id, err := s.Add(1, "rerun"); err != nil || id != 1 {
+		t.Errorf("Finished attempt must be replaced, got id %d, error: %v", id, err)
+	}
+}
+
+func TestStore_Eviction(t *testing.T) {
+	s := New[int](Config{MaxFinished: 2, TTL: 50 * time.Millisecond})
+	for i := 1; i <= 3; i++ {
+		id, err := s.Add(0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Finish(id, i)
+	}
+	running, _ := s.Add(0, 0)
+	if _, _, ok := s.Get(1); ok {
+		t.Errorf("The oldest finished attempt must be removed")
+	}
+	for _, id := range []int64{2, 3, running} {
+		if _, _, ok := s.Get(id); !ok {
+			t.Errorf("Attempt %d must be kept", id)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	s.Add(0, 0)
+	for _, id := range []int64{2, 3} {
+		if _, _, ok := s.Get(id); ok {
+			t.Errorf("Expired attempt %d must be removed", id)
+		}
+	}
+	if _, finished, ok := s.Get(running); !ok || finished {
+		t.Errorf("Running attempt must never be removed")
+	}
+}
@@ -4,21 +4,94 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	TransportRabbitMQ = "rabbitmq"
+	TransportHTTP     = "http"
+	TransportGRPC     = "grpc"
+	TransportNATS     = "nats"
+	TransportRedis    = "redis"
+)
+
+const (
+	StorageS3 = "s3"
+	StorageFS = "fs"
+)
+
 type Config struct {
+	// Where test files are read from: s3 or fs
+	StorageBackend string `env:"STORAGE_BACKEND" env-default:"s3"`
+	// Root directory of the fs storage backend
+	StoragePath      string `env:"STORAGE_PATH" env-default:"tasks"`
 	S3Endpoint       string `env:"S3_ENDPOINT" env-default:"127.0.0.1:8333"`
-	S3AccessKey      string `env:"S3_ACCESS_KEY" env-required:"true"`
-	S3SecretKey      string `env:"S3_SECRET_KEY" env-required:"true"`
+	S3AccessKey      string `env:"S3_ACCESS_KEY"`
+	S3SecretKey      string `env:"S3_SECRET_KEY"`
 	S3Bucket         string `env:"S3_BUCKET" env-default:"tasks"`
 	RabbitMQHost     string `env:"RABBIT_HOST" env-default:"127.0.0.1"`
 	RabbitMQPort     int    `env:"RABBIT_PORT" env-default:"5672"`
-	RabbitMQUser     string `env:"RABBIT_USER" env-required:"true"`
-	RabbitMQPassword string `env:"RABBIT_PASSWORD" env-required:"true"`
-	WorkersCount     int    `env:"WORKERS_COUNT" env-default:"0"`
-	LogLevel         string `env:"LOG_LEVEL" env-default:"warn"`
+	RabbitMQUser     string `env:"RABBIT_USER"`
+	RabbitMQPassword string `env:"RABBIT_PASSWORD"`
+	// Request queues in format name[:weight[:workers]], separated by comma
+	RabbitMQQueues      []string `env:"RABBIT_QUEUES" env-default:"rankode-req" env-separator:","`
+	RabbitMQMaxPriority int      `env:"RABBIT_MAX_PRIORITY" env-default:"0"`
+	// Used for requests without reply-to property
+	RabbitMQResponseQueue string `env:"RABBIT_RESPONSE_QUEUE" env-default:"rankode-resp"`
+	// Exchange for attempt progress events, disabled if empty
+	RabbitMQEventsExchange string `env:"RABBIT_EVENTS_EXCHANGE" env-default:""`
+	RabbitMQCancelExchange string `env:"RABBIT_CANCEL_EXCHANGE" env-default:"rankode-cancel"`
+	NATSURL                string `env:"NATS_URL" env-default:"nats://127.0.0.1:4222"`
+	// JetStream stream with attempt requests, created if not exists
+	NATSStream          string `env:"NATS_STREAM" env-default:"RANKODE"`
+	NATSSubject         string `env:"NATS_SUBJECT" env-default:"rankode.req"`
+	NATSDurable         string `env:"NATS_DURABLE" env-default:"rankode-runner"`
+	NATSResponseSubject string `env:"NATS_RESPONSE_SUBJECT" env-default:"rankode.resp"`
+	// Prefix of subjects for attempt progress events, disabled if empty
+	NATSEventsSubject string        `env:"NATS_EVENTS_SUBJECT" env-default:""`
+	NATSCancelSubject string        `env:"NATS_CANCEL_SUBJECT" env-default:"rankode.cancel"`
+	NATSAckWait       time.Duration `env:"NATS_ACK_WAIT" env-default:"30s"`
+	NATSMaxDeliver    int           `env:"NATS_MAX_DELIVER" env-default:"3"`
+	RedisAddr         string        `env:"REDIS_ADDR" env-default:"127.0.0.1:6379"`
+	RedisPassword     string        `env:"REDIS_PASSWORD" env-default:""`
+	RedisDB           int           `env:"REDIS_DB" env-default:"0"`
+	RedisStream       string        `env:"REDIS_STREAM" env-default:"rankode-req"`
+	RedisGroup        string        `env:"REDIS_GROUP" env-default:"rankode-runner"`
+	// Consumer name of this runner, defaults to hostname
+	RedisConsumer       string `env:"REDIS_CONSUMER" env-default:""`
+	RedisResponseStream string `env:"REDIS_RESPONSE_STREAM" env-default:"rankode-resp"`
+	RedisResponseMaxLen int64  `env:"REDIS_RESPONSE_MAXLEN" env-default:"100000"`
+	// Prefix of pub/sub channels for attempt progress events, disabled if empty
+	RedisEventsChannel string        `env:"REDIS_EVENTS_CHANNEL" env-default:""`
+	RedisCancelChannel string        `env:"REDIS_CANCEL_CHANNEL" env-default:"rankode-cancel"`
+	RedisClaimIdle     time.Duration `env:"REDIS_CLAIM_IDLE" env-default:"1m"`
+	RedisMaxDeliver    int64         `env:"REDIS_MAX_DELIVER" env-default:"3"`
+	// Enabled job transports separated by comma: rabbitmq, nats, redis, http, grpc
+	Transports []string `env:"TRANSPORTS" env-default:"rabbitmq" env-separator:","`
+	HTTPAddr   string   `env:"HTTP_ADDR" env-default:":8080"`
+	GRPCAddr   string   `env:"GRPC_ADDR" env-default:":9090"`
+	// Max attempts accepted by each of HTTP and gRPC APIs and not finished yet,
+	// further ones are rejected
+	MaxPendingAttempts int `env:"MAX_PENDING_ATTEMPTS" env-default:"256"`
+	// Max finished attempts the HTTP and gRPC transports keep for fetching results,
+	// the oldest are removed first
+	MaxStoredResults int `env:"MAX_STORED_RESULTS" env-default:"1000"`
+	// Max total time of one attempt: files loading, build and all tests
+	AttemptTimeout time.Duration `env:"ATTEMPT_TIMEOUT" env-default:"10m"`
+	// Memory limit in bytes of attempts without one
+	DefaultMemoryLimit int64 `env:"DEFAULT_MEMORY_LIMIT" env-default:"268435456"`
+	// Lower memory limits of attempts are raised to this many bytes
+	MinMemoryLimit int64 `env:"MIN_MEMORY_LIMIT" env-default:"33554432"`
+	// Max total size in bytes of inline test inputs and expected outputs of one attempt
+	MaxInlineSize int `env:"MAX_INLINE_SIZE" env-default:"1048576"`
+	// Directory with unpacked problem packages
+	PackagesDir string `env:"PACKAGES_DIR" env-default:"/tmp/rankode-packages"`
+	// Max unpacked size in bytes of one problem package
+	PackageMaxSize int64  `env:"PACKAGE_MAX_SIZE" env-default:"1073741824"`
+	WorkersCount   int    `env:"WORKERS_COUNT" env-default:"0"`
+	LogLevel       string `env:"LOG_LEVEL" env-default:"warn"`
 }
 
 func NewConfig() (*Config, error) {
@@ -34,6 +107,26 @@ func NewConfig() (*Config, error) {
 	if cfg.WorkersCount == 0 {
 		cfg.WorkersCount = runtime.NumCPU()
 	}
+	switch cfg.StorageBackend {
+	case StorageS3:
+		if cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, fmt.Errorf("S3_ACCESS_KEY and S3_SECRET_KEY are required for %s storage", cfg.StorageBackend)
+		}
+	case StorageFS:
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+	for _, transport := range cfg.Transports {
+		switch transport {
+		case TransportRabbitMQ:
+			if cfg.RabbitMQUser == "" || cfg.RabbitMQPassword == "" {
+				return nil, fmt.Errorf("RABBIT_USER and RABBIT_PASSWORD are required for %s transport", transport)
+			}
+		case TransportNATS, TransportRedis, TransportHTTP, TransportGRPC:
+		default:
+			return nil, fmt.Errorf("unknown transport %q", transport)
+		}
+	}
 
 	return cfg, nil
 }
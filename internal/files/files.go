@@ -2,6 +2,7 @@ package files
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/minio/minio-go/v7"
@@ -20,15 +21,15 @@ type Config struct {
 	Bucket    string
 }
 
-func NewFileStorage(cfg Config) *FileStorage {
+func NewFileStorage(cfg Config) (*FileStorage, error) {
 	client, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
 		Secure: false,
 	})
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
 	}
-	return &FileStorage{cl: client, Bucket: cfg.Bucket}
+	return &FileStorage{cl: client, Bucket: cfg.Bucket}, nil
 }
 
 func (s *FileStorage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
@@ -0,0 +1,38 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage reads files from a local directory tree, names are slash separated
+// paths relative to the root. Names leaving the root, also through symlinks, are rejected.
+type LocalStorage struct {
+	root *os.Root
+}
+
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage directory: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+func (s *LocalStorage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	file, err := s.root.Open(filepath.FromSlash(filename))
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) Close() error {
+	return s.root.Close()
+}
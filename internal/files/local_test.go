@@ -0,0 +1,53 @@
+package files
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage_GetFile(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "tasks")
+	if err := os.MkdirAll(filepath.Join(root, "1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "1", "in1"), []byte("42"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	storage, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	file, err := storage.GetFile(context.Background(), "1/in1")
+	if err != nil {
+		t.Fatalf("GetFile failed: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	if string(data) != "42" {
+		t.Fatalf("Unexpected content: %q", data)
+	}
+
+	for _, name := range []string{"../secret", "1/../../secret", "/etc/passwd", "link", "missing"} {
+		if _, err := storage.GetFile(context.Background(), name); err == nil {
+			t.Errorf("GetFile(%q) must fail", name)
+		}
+	}
+}
+
+func TestNewLocalStorage_MissingDir(t *testing.T) {
+	if _, err := NewLocalStorage(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("NewLocalStorage must fail for missing directory")
+	}
+}
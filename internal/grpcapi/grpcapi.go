@@ -0,0 +1,207 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/cutekitek/rankode-runner/internal/attemptstore"
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/mappers"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/pkg/runnerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Max events kept for an attempt, later ones are not streamed. The result is
+// always kept.
+const maxEvents = 1024
+
+type Config struct {
+	Addr string
+	// Max attempts received and not finished, requests beyond it fail with
+	// ResourceExhausted
+	MaxPending int
+	// Max finished attempts kept, the oldest are removed first
+	MaxResults int
+}
+
+type attempt struct {
+	task    *models.AttemptRequest
+	cancel  context.CancelFunc
+	updates []*runnerpb.RunUpdate
+	// closed and replaced on every new update
+	notify   chan struct{}
+	finished bool
+}
+
+// Server implements runnerpb.RunnerServer on top of the judge.
+type Server struct {
+	runnerpb.UnimplementedRunnerServer
+	cfg    Config
+	judge  *judge.Judge
+	server *grpc.Server
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	attempts *attemptstore.Store[*attempt]
+	// guards updates of attempts
+	mu sync.Mutex
+}
+
+func NewServer(cfg Config, judge *judge.Judge) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cfg:    cfg,
+		judge:  judge,
+		server: grpc.NewServer(),
+		wg:     &sync.WaitGroup{},
+		ctx:    ctx,
+		cancel: cancel,
+		attempts: attemptstore.New[*attempt](attemptstore.Config{
+			MaxPending:  cfg.MaxPending,
+			MaxFinished: cfg.MaxResults,
+		}),
+	}
+	runnerpb.RegisterRunnerServer(s.server, s)
+	return s
+}
+
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.server.Serve(ln); err != nil {
+			slog.Error("grpc server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Close() {
+	s.cancel()
+	s.server.GracefulStop()
+	s.wg.Wait()
+}
+
+func (s *Server) Run(req *runnerpb.AttemptRequest, stream grpc.ServerStreamingServer[runnerpb.RunUpdate]) error {
+	a, err := s.submit(req)
+	if err != nil {
+		return err
+	}
+	// Nobody will get the result if the client is gone
+	defer a.cancel()
+	return s.stream(stream.Context(), a, stream.Send)
+}
+
+func (s *Server) Submit(ctx context.Context, req *runnerpb.AttemptRequest) (*runnerpb.SubmitResponse, error) {
+	a, err := s.submit(req)
+	if err != nil {
+		return nil, err
+	}
+	return &runnerpb.SubmitResponse{Id: a.task.Id}, nil
+}
+
+func (s *Server) Cancel(ctx context.Context, req *runnerpb.CancelRequest) (*runnerpb.CancelResponse, error) {
+	a, _, ok := s.attempts.Get(req.GetId())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "attempt not found")
+	}
+	a.cancel()
+	return &runnerpb.CancelResponse{}, nil
+}
+
+func (s *Server) StreamEvents(req *runnerpb.StreamEventsRequest, stream grpc.ServerStreamingServer[runnerpb.RunUpdate]) error {
+	a, _, ok := s.attempts.Get(req.GetId())
+	if !ok {
+		return status.Error(codes.NotFound, "attempt not found")
+	}
+	return s.stream(stream.Context(), a, stream.Send)
+}
+
+// submit registers the attempt and starts processing it in background
+func (s *Server) submit(req *runnerpb.AttemptRequest) (*attempt, error) {
+	task := mappers.AttemptRequestFromProto(req)
+	ctx, cancel := context.WithCancel(s.ctx)
+	a := &attempt{task: task, cancel: cancel, notify: make(chan struct{})}
+	id, err := s.attempts.Add(task.Id, a)
+	if errors.Is(err, attemptstore.ErrFull) {
+		cancel()
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	} else if err != nil {
+		cancel()
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	task.Id = id
+
+	s.publish(a, &runnerpb.RunUpdate{Update: &runnerpb.RunUpdate_Event{
+		Event: mappers.AttemptEventToProto(mappers.RunEventToAttemptEvent(task, dto.RunEvent{Type: models.AttemptEventQueued})),
+	}})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		resp := s.judge.Process(ctx, task, func(event dto.RunEvent) {
+			s.publish(a, &runnerpb.RunUpdate{Update: &runnerpb.RunUpdate_Event{
+				Event: mappers.AttemptEventToProto(mappers.RunEventToAttemptEvent(task, event)),
+			}})
+		})
+		s.publish(a, &runnerpb.RunUpdate{Update: &runnerpb.RunUpdate_Result{
+			Result: mappers.AttemptResponseToProto(resp),
+		}})
+	}()
+	return a, nil
+}
+
+func (s *Server) publish(a *attempt, update *runnerpb.RunUpdate) {
+	result := update.GetResult() != nil
+	s.mu.Lock()
+	if !result && len(a.updates) >= maxEvents {
+		s.mu.Unlock()
+		return
+	}
+	a.updates = append(a.updates, update)
+	a.finished = result
+	close(a.notify)
+	a.notify = make(chan struct{})
+	s.mu.Unlock()
+	if result {
+		s.attempts.Finish(a.task.Id, a)
+	}
+}
+
+// stream sends all updates of the attempt until the result
+func (s *Server) stream(ctx context.Context, a *attempt, send func(*runnerpb.RunUpdate) error) error {
+	sent := 0
+	for {
+		s.mu.Lock()
+		updates := a.updates[sent:]
+		notify := a.notify
+		finished := a.finished
+		s.mu.Unlock()
+
+		for _, update := range updates {
+			if err := send(update); err != nil {
+				return err
+			}
+		}
+		sent += len(updates)
+		if finished {
+			return nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+	"github.com/cutekitek/rankode-runner/pkg/runnerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_MaxPending(t *testing.T) {
+	runner := &testutil.EchoRunner{Delay: time.Minute}
+	s := NewServer(Config{MaxPending: 1}, judge.NewJudge(judge.Config{Workers: 1}, runner, testutil.Storage{}, nil))
+	defer s.Close()
+	input := "1"
+	req := &runnerpb.AttemptRequest{TestCases: []*runnerpb.TestCase{{Id: 1, Input: &input}}}
+
+	resp, err := s.Submit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if _, err := s.Submit(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Submit must be rejected while the attempt is pending, got %v", err)
+	}
+
+	if _, err := s.Cancel(context.Background(), &runnerpb.CancelRequest{Id: resp.Id}); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := s.Submit(context.Background(), req)
+		if err == nil {
+			break
+		}
+		if status.Code(err) != codes.ResourceExhausted || time.Now().After(deadline) {
+			t.Fatalf("Finished attempt must free its place, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_MaxEvents(t *testing.T) {
+	s := NewServer(Config{}, judge.NewJudge(judge.Config{}, &testutil.EchoRunner{}, testutil.Storage{}, nil))
+	defer s.Close()
+	req := &runnerpb.AttemptRequest{}
+	for i := range maxEvents + 10 {
+		input := "1"
+		req.TestCases = append(req.TestCases, &runnerpb.TestCase{Id: int64(i), Input: &input})
+	}
+
+	a, err := s.submit(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var updates []*runnerpb.RunUpdate
+	err = s.stream(context.Background(), a, func(update *runnerpb.RunUpdate) error {
+		updates = append(updates, update)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != maxEvents+1 || updates[maxEvents].GetResult() == nil {
+		t.Fatalf("Expected %d events and the result, got %d updates", maxEvents, len(updates))
+	}
+}
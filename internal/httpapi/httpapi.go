@@ -0,0 +1,176 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/attemptstore"
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+const maxRequestSize = 64 * 1024 * 1024
+
+type Config struct {
+	Addr string
+	// Max attempts received and not finished, requests beyond it get 503
+	MaxPending int
+	// Max results of asynchronous attempts kept, the oldest are removed first
+	MaxResults int
+}
+
+// Server exposes the judge over HTTP with JSON bodies mirroring AttemptRequest and AttemptResponse.
+//
+//	POST /run            runs the attempt and returns the response
+//	POST /attempts       queues the attempt and returns {"id": ...} immediately
+//	GET  /attempts/{id}  returns the response, status is Created while the attempt runs
+type Server struct {
+	cfg    Config
+	judge  *judge.Judge
+	server *http.Server
+	// Results of asynchronous attempts, nil while the attempt runs. Attempts of
+	// /run take a pending place too.
+	attempts *attemptstore.Store[*models.AttemptResponse]
+	wg       *sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewServer(cfg Config, judge *judge.Judge) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		cfg:   cfg,
+		judge: judge,
+		attempts: attemptstore.New[*models.AttemptResponse](attemptstore.Config{
+			MaxPending:  cfg.MaxPending,
+			MaxFinished: cfg.MaxResults,
+		}),
+		wg:     &sync.WaitGroup{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /run", s.run)
+	mux.HandleFunc("POST /attempts", s.submit)
+	mux.HandleFunc("GET /attempts/{id}", s.get)
+	mux.HandleFunc("GET /languages", s.languages)
+	s.server = &http.Server{Addr: cfg.Addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("http server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		slog.Error("failed to shutdown http server", "error", err)
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Server) run(w http.ResponseWriter, r *http.Request) {
+	if err := s.attempts.Acquire(); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	defer s.attempts.Release()
+	task, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.judge.Process(r.Context(), task, nil))
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	task, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := s.attempts.Add(task.Id, nil)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	task.Id = id
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.attempts.Finish(task.Id, s.judge.Process(s.ctx, task, nil))
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]int64{"id": task.Id})
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid attempt id")
+		return
+	}
+	resp, finished, ok := s.attempts.Get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "attempt not found")
+		return
+	}
+	if !finished {
+		resp = &models.AttemptResponse{Id: id, Status: models.AttemptStatusCreated}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// languages lists languages the runner accepts
+func (s *Server) languages(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]models.Language{"languages": s.judge.Languages()})
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*models.AttemptRequest, bool) {
+	task := new(models.AttemptRequest)
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(task); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return nil, false
+	}
+	return task, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// writeStoreError responds to a request the attempt store rejected
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, attemptstore.ErrFull) {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeError(w, http.StatusConflict, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
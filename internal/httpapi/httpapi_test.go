@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+)
+
+const attemptBody = `{"test_cases": [{"id": 1, "input": "1"}]}`
+
+func post(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(attemptBody)))
+	return rec
+}
+
+func TestServer_MaxPending(t *testing.T) {
+	runner := &testutil.EchoRunner{Delay: 200 * time.Millisecond}
+	s := NewServer(Config{MaxPending: 1}, judge.NewJudge(judge.Config{Workers: 1}, runner, testutil.Storage{}, nil))
+	defer s.Close()
+	h := s.server.Handler
+
+	if rec := post(t, h, "/attempts"); rec.Code != http.StatusAccepted {
+		t.Fatalf("Unexpected status: %d %s", rec.Code, rec.Body)
+	}
+	for _, path := range []string{"/attempts", "/run"} {
+		if rec := post(t, h, path); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s must be rejected while the attempt is pending, got %d", path, rec.Code)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/attempts/1", nil))
+		var resp models.AttemptResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if resp.Status == models.AttemptStatusSuccessful {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Attempt is not finished: %s", rec.Body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	// The place is released right after the result is stored
+	for {
+		rec := post(t, h, "/run")
+		if rec.Code == http.StatusOK {
+			break
+		}
+		if rec.Code != http.StatusServiceUnavailable || time.Now().After(deadline) {
+			t.Fatalf("Finished attempt must free its place, got %d", rec.Code)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_MaxResults(t *testing.T) {
+	s := NewServer(Config{MaxResults: 1}, judge.NewJudge(judge.Config{}, &testutil.EchoRunner{}, testutil.Storage{}, nil))
+	defer s.Close()
+	h := s.server.Handler
+
+	for range 2 {
+		if rec := post(t, h, "/attempts"); rec.Code != http.StatusAccepted {
+			t.Fatalf("Unexpected status: %d %s", rec.Code, rec.Body)
+		}
+		s.wg.Wait()
+	}
+	for id, code := range map[string]int{"1": http.StatusNotFound, "2": http.StatusOK} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/attempts/"+id, nil))
+		if rec.Code != code {
+			t.Errorf("Unexpected status of attempt %s: %d", id, rec.Code)
+		}
+	}
+}
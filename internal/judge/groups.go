@@ -0,0 +1,64 @@
+package judge
+
+import (
+	"github.com/cutekitek/rankode-runner/internal/problems"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+// scoreGroups returns results of groups with tests in the attempt. A group is passed
+// if all its tests are complete and matched, and scores its points if all groups it
+// depends on, directly or not, are passed too. Dependencies without tests in the
+// attempt are not passed.
+func scoreGroups(groups []problems.Group, tests []models.TestStatus) []models.GroupResult {
+	if len(groups) == 0 {
+		return nil
+	}
+	passed := make(map[string]bool)
+	for _, test := range tests {
+		if test.Group == "" {
+			continue
+		}
+		ok := test.Status == models.TestCaseStatusComplete && test.Matched != nil && *test.Matched
+		if prev, seen := passed[test.Group]; seen {
+			ok = ok && prev
+		}
+		passed[test.Group] = ok
+	}
+
+	deps := make(map[string][]string, len(groups))
+	for _, g := range groups {
+		deps[g.Name] = g.Dependencies
+	}
+	// visiting guards against cycles, which manifest validation doesn't rule out
+	visiting := make(map[string]bool)
+	scored := make(map[string]bool)
+	var scores func(name string) bool
+	scores = func(name string) bool {
+		if ok, done := scored[name]; done {
+			return ok
+		}
+		if visiting[name] {
+			return false
+		}
+		visiting[name] = true
+		ok := passed[name]
+		for _, dep := range deps[name] {
+			ok = scores(dep) && ok
+		}
+		scored[name] = ok
+		return ok
+	}
+
+	var results []models.GroupResult
+	for _, g := range groups {
+		if _, ok := passed[g.Name]; !ok {
+			continue
+		}
+		result := models.GroupResult{Name: g.Name, Points: g.Points, Passed: passed[g.Name]}
+		if scores(g.Name) {
+			result.Score = g.Points
+		}
+		results = append(results, result)
+	}
+	return results
+}
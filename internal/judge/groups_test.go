@@ -0,0 +1,52 @@
+package judge
+
+import (
+	"testing"
+
+	"github.com/cutekitek/rankode-runner/internal/problems"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+func TestScoreGroups(t *testing.T) {
+	yes, no := true, false
+	groups := []problems.Group{
+		{Name: "samples"},
+		{Name: "easy", Points: 30, Dependencies: []string{"samples"}},
+		{Name: "hard", Points: 70, Dependencies: []string{"easy"}},
+		{Name: "loop", Points: 10, Dependencies: []string{"loop"}},
+		{Name: "unused", Points: 5},
+	}
+	tests := []models.TestStatus{
+		{Group: "samples", Matched: &yes},
+		{Group: "samples", Status: models.TestCaseStatusTimeout, Matched: &yes},
+		{Group: "easy", Matched: &yes},
+		{Group: "hard", Matched: &yes},
+		{Group: "loop", Matched: &yes},
+		{Matched: &no},
+	}
+
+	results := scoreGroups(groups, tests)
+	expected := []models.GroupResult{
+		{Name: "samples"},
+		{Name: "easy", Points: 30, Passed: true},
+		{Name: "hard", Points: 70, Passed: true},
+		{Name: "loop", Points: 10, Passed: true},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Unexpected groups: %+v", results)
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("Unexpected group %d: %+v", i, results[i])
+		}
+	}
+
+	// Dependencies are passed now, except the cycle
+	tests[1].Status = models.TestCaseStatusComplete
+	results = scoreGroups(groups, tests)
+	for i, score := range []float64{0, 30, 70, 0} {
+		if results[i].Score != score {
+			t.Errorf("Unexpected score of %s: %v", results[i].Name, results[i].Score)
+		}
+	}
+}
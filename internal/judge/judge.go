@@ -0,0 +1,376 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"runtime/debug"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/mappers"
+	"github.com/cutekitek/rankode-runner/internal/problems"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/runner"
+	"github.com/pkg/errors"
+)
+
+type FileStorage interface {
+	GetFile(ctx context.Context, filename string) (io.Reader, error)
+}
+
+type PackageStore interface {
+	Get(ctx context.Context, name, version string) (*problems.Package, error)
+}
+
+type Config struct {
+	// Max total time of an attempt including files loading, build and all tests.
+	// Zero means no limit
+	AttemptTimeout time.Duration
+	// Max total size of inline test inputs and expected outputs of an attempt.
+	// Zero means no limit
+	MaxInlineSize int
+	// Memory limit in bytes of attempts which neither set one nor use a package
+	// with one. Zero means no limit
+	DefaultMemoryLimit int64
+	// Lower memory limits are raised to this many bytes
+	MinMemoryLimit int64
+	// Max attempts processed at once by all transports, others wait for a free
+	// slot. Zero means no limit
+	Workers int
+}
+
+// Judge loads attempt files from storage, runs the attempt and converts the result
+// to a response. It is shared by all transports.
+type Judge struct {
+	cfg         Config
+	runner      runner.Runner
+	fileStorage FileStorage
+	packages    PackageStore
+	// nil if the number of attempts is not limited
+	slots chan struct{}
+}
+
+// NewJudge creates judge, packages may be nil if problem packages are not used
+func NewJudge(cfg Config, runner runner.Runner, storage FileStorage, packages PackageStore) *Judge {
+	j := &Judge{cfg: cfg, runner: runner, fileStorage: storage, packages: packages}
+	if cfg.Workers > 0 {
+		j.slots = make(chan struct{}, cfg.Workers)
+	}
+	return j
+}
+
+// Process runs a single attempt. Any failure, including a panic, is turned into
+// a response, so it never returns nil. onEvent may be nil.
+func (j *Judge) Process(ctx context.Context, task *models.AttemptRequest, onEvent func(dto.RunEvent)) (resp *models.AttemptResponse) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("panic while processing task", "id", task.Id, "panic", rec, "stack", string(debug.Stack()))
+			resp = &models.AttemptResponse{
+				Id:     task.Id,
+				Status: models.AttemptStatusInternalError,
+				Error:  fmt.Sprintf("internal error: %v", rec),
+			}
+		}
+	}()
+
+	// The attempt timeout doesn't include waiting for a slot
+	if j.slots != nil {
+		select {
+		case j.slots <- struct{}{}:
+		case <-ctx.Done():
+			return &models.AttemptResponse{Id: task.Id, Status: models.AttemptStatusCancelled}
+		}
+		defer func() { <-j.slots }()
+	}
+
+	if j.cfg.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.cfg.AttemptTimeout)
+		defer cancel()
+	}
+
+	if ctx.Err() != nil {
+		return &models.AttemptResponse{Id: task.Id, Status: models.AttemptStatusCancelled}
+	}
+
+	if size := inlineSize(task); j.cfg.MaxInlineSize > 0 && size > j.cfg.MaxInlineSize {
+		return &models.AttemptResponse{
+			Id:     task.Id,
+			Status: models.AttemptStatusInternalError,
+			Error:  fmt.Sprintf("inline test data is too large: %d bytes, max %d", size, j.cfg.MaxInlineSize),
+		}
+	}
+
+	var (
+		checker     *dto.Checker
+		attachments []dto.File
+		groups      []problems.Group
+	)
+	if task.Package != "" {
+		var err error
+		if checker, attachments, groups, err = j.applyPackage(ctx, task); err != nil {
+			if resp := j.interrupted(ctx, task, err); resp != nil {
+				return resp
+			}
+			slog.Error("failed to load problem package", "package", task.Package, "error", err)
+			return &models.AttemptResponse{
+				Id:     task.Id,
+				Status: models.AttemptStatusInternalError,
+				Error:  fmt.Sprintf("failed to load package %s: %s", task.Package, err),
+			}
+		}
+	}
+
+	task.MemoryLimit = j.memoryLimit(task.MemoryLimit)
+	request := &dto.RunRequest{
+		Image:         task.Language,
+		Code:          task.Code,
+		Timeout:       time.Duration(task.Timeout) * time.Millisecond,
+		MemoryLimit:   int(task.MemoryLimit),
+		MaxOutputSize: int(task.MaxOutputSize),
+		Attachments:   attachments,
+		InputFile:     task.InputFile,
+		OutputFile:    task.OutputFile,
+		Checker:       checker,
+		OnEvent:       onEvent,
+	}
+
+	files, err := submissionFiles(task)
+	if err != nil {
+		return &models.AttemptResponse{Id: task.Id, Status: models.AttemptStatusInternalError, Error: err.Error()}
+	}
+	request.Files = files
+
+	for _, a := range task.Attachments {
+		data, err := j.loadFile(ctx, a.FileName)
+		if err != nil {
+			if resp := j.interrupted(ctx, task, err); resp != nil {
+				return resp
+			}
+			slog.Error("failed to load attachment", "error", err)
+			return &models.AttemptResponse{
+				Id:     task.Id,
+				Status: models.AttemptStatusInternalError,
+				Error:  fmt.Sprintf("failed to load attachment %s: %s", a.FileName, err),
+			}
+		}
+		request.Attachments = append(request.Attachments, dto.File{Path: a.Path, Content: data})
+	}
+
+	if task.VerificationFileName != "" {
+		data, err := j.loadFile(ctx, task.VerificationFileName)
+		if err != nil {
+			if resp := j.interrupted(ctx, task, err); resp != nil {
+				return resp
+			}
+			slog.Error("failed to load verification file", "error", err)
+			return &models.AttemptResponse{
+				Id:     task.Id,
+				Status: models.AttemptStatusInternalError,
+				Error:  fmt.Sprintf("failed to load verification file %s: %s", task.VerificationFileName, err),
+			}
+		}
+		request.VerificationCode = data
+	}
+
+	for _, test := range task.TestCases {
+		expected := ""
+		if test.Expected != nil {
+			expected = *test.Expected
+		}
+		request.Expected = append(request.Expected, expected)
+		if test.Input != nil {
+			request.Input = append(request.Input, *test.Input)
+			continue
+		}
+		data, err := j.loadFile(ctx, test.InputFileName)
+		if err != nil {
+			if resp := j.interrupted(ctx, task, err); resp != nil {
+				return resp
+			}
+			slog.Error("failed to load test file", "error", err)
+			return &models.AttemptResponse{
+				Id:     task.Id,
+				Status: models.AttemptStatusInternalError,
+				Error:  fmt.Sprintf("failed to load test file %s: %s", test.InputFileName, err),
+			}
+		}
+		request.Input = append(request.Input, data)
+	}
+
+	result, err := j.runner.Run(ctx, request)
+	if err != nil {
+		if resp := j.interrupted(ctx, task, err); resp != nil {
+			return resp
+		}
+	}
+	if errors.Is(err, runner.ErrInvalidRequest) {
+		return &models.AttemptResponse{Id: task.Id, Status: models.AttemptStatusInternalError, Error: err.Error()}
+	}
+	if err != nil {
+		slog.Error("failed to run task", "error", err)
+		return &models.AttemptResponse{
+			Id:     task.Id,
+			Status: models.AttemptStatusInternalError,
+		}
+	}
+	resp = mappers.RunResultToAttemptResult(task, result)
+	resp.Groups = scoreGroups(groups, resp.Tests)
+	return resp
+}
+
+// memoryLimit applies the default and the floor to the limit of the attempt. Limits
+// were ignored before, so older clients may send values far too low to run anything.
+func (j *Judge) memoryLimit(limit int64) int64 {
+	if limit <= 0 {
+		limit = j.cfg.DefaultMemoryLimit
+	}
+	if limit > 0 && limit < j.cfg.MinMemoryLimit {
+		limit = j.cfg.MinMemoryLimit
+	}
+	return limit
+}
+
+// interrupted returns the response for an attempt stopped by cancellation or its
+// total time limit, or nil if err has another cause. Storage clients don't always
+// wrap context errors, so the context itself is checked too.
+func (j *Judge) interrupted(ctx context.Context, task *models.AttemptRequest, err error) *models.AttemptResponse {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.Canceled) {
+		return &models.AttemptResponse{Id: task.Id, Status: models.AttemptStatusCancelled}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("attempt deadline exceeded", "id", task.Id, "timeout", j.cfg.AttemptTimeout)
+		return &models.AttemptResponse{
+			Id:     task.Id,
+			Status: models.AttemptStatusInternalError,
+			Error:  fmt.Sprintf("attempt exceeded total time limit of %s", j.cfg.AttemptTimeout),
+		}
+	}
+	return nil
+}
+
+// Languages returns languages of the runner or nil if the runner doesn't list them
+func (j *Judge) Languages() []models.Language {
+	if l, ok := j.runner.(runner.LanguageLister); ok {
+		return l.Languages()
+	}
+	return nil
+}
+
+// applyPackage fills tests and missing limits of the attempt from its package and
+// returns the checker of the package if any, its attachments and test groups
+func (j *Judge) applyPackage(ctx context.Context, task *models.AttemptRequest) (*dto.Checker, []dto.File, []problems.Group, error) {
+	if j.packages == nil {
+		return nil, nil, nil, errors.New("problem packages are not configured")
+	}
+	pkg, err := j.packages.Get(ctx, task.Package, task.PackageVersion)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m := pkg.Manifest
+	if task.Timeout == 0 {
+		task.Timeout = m.TimeLimit
+	}
+	if task.MemoryLimit == 0 {
+		task.MemoryLimit = m.MemoryLimit
+	}
+	if task.MaxOutputSize == 0 {
+		task.MaxOutputSize = m.MaxOutputSize
+	}
+	if task.InputFile == "" && task.OutputFile == "" {
+		task.InputFile, task.OutputFile = m.InputFile, m.OutputFile
+	}
+
+	tests := make(map[int64]problems.Test, len(m.Tests))
+	for _, t := range m.Tests {
+		tests[t.Id] = t
+	}
+	if len(task.TestCases) == 0 {
+		for i, t := range m.Tests {
+			task.TestCases = append(task.TestCases, models.TestCase{Id: t.Id, Order: int32(i)})
+		}
+	}
+	for i := range task.TestCases {
+		tc := &task.TestCases[i]
+		if tc.Input != nil || tc.InputFileName != "" {
+			continue
+		}
+		t, ok := tests[tc.Id]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("test %d not found in package", tc.Id)
+		}
+		input, err := pkg.ReadFile(t.Input)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		tc.Input = &input
+		tc.Group = t.Group
+		if t.Expected != "" && tc.Expected == nil {
+			expected, err := pkg.ReadFile(t.Expected)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			tc.Expected = &expected
+		}
+	}
+
+	attachments := make([]dto.File, 0, len(m.Attachments))
+	for _, a := range m.Attachments {
+		data, err := pkg.ReadFile(a.File)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to read attachment")
+		}
+		attachments = append(attachments, dto.File{Path: a.Path, Content: data})
+	}
+
+	if m.Checker == nil {
+		return nil, attachments, m.Groups, nil
+	}
+	code, err := pkg.ReadFile(m.Checker.File)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to read checker")
+	}
+	checker := &dto.Checker{Image: m.Checker.Language, Code: code, Files: make(map[string]string, len(m.Checker.Files))}
+	for _, name := range m.Checker.Files {
+		data, err := pkg.ReadFile(name)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to read checker file")
+		}
+		checker.Files[path.Base(name)] = data
+	}
+	return checker, attachments, m.Groups, nil
+}
+
+func inlineSize(task *models.AttemptRequest) int {
+	size := 0
+	for _, test := range task.TestCases {
+		if test.Input != nil {
+			size += len(*test.Input)
+		}
+		if test.Expected != nil {
+			size += len(*test.Expected)
+		}
+	}
+	return size
+}
+
+func (j *Judge) loadFile(ctx context.Context, name string) (string, error) {
+	file, err := j.fileStorage.GetFile(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if closer, ok := file.(io.Closer); ok {
+		defer closer.Close()
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
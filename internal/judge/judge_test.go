@@ -0,0 +1,335 @@
+package judge
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/problems"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+)
+
+type noStorage struct{}
+
+func (noStorage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
+	return nil, errors.New("storage must not be used")
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestJudge_InlineTests(t *testing.T) {
+	j := NewJudge(Config{MaxInlineSize: 64}, &testutil.EchoRunner{}, noStorage{}, nil)
+	resp := j.Process(context.Background(), &models.AttemptRequest{Id: 1, TestCases: []models.TestCase{
+		{Id: 1, Input: ptr("1 2\n")},
+		{Id: 2, Input: ptr("3\n"), Expected: ptr("3  \n\n")},
+		{Id: 3, Input: ptr("4\n"), Expected: ptr("5\n")},
+	}}, nil)
+	if resp.Status != models.AttemptStatusSuccessful || len(resp.Tests) != 3 {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if resp.Tests[0].Output != "1 2\n" || resp.Tests[0].Matched != nil {
+		t.Errorf("Unexpected first test: %+v", resp.Tests[0])
+	}
+	if resp.Tests[1].Matched == nil || !*resp.Tests[1].Matched {
+		t.Errorf("Second test must match: %+v", resp.Tests[1])
+	}
+	if resp.Tests[2].Matched == nil || *resp.Tests[2].Matched {
+		t.Errorf("Third test must not match: %+v", resp.Tests[2])
+	}
+
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, TestCases: []models.TestCase{
+		{Id: 1, Input: ptr(string(make([]byte, 40))), Expected: ptr(string(make([]byte, 40)))},
+	}}, nil)
+	if resp.Status != models.AttemptStatusInternalError {
+		t.Fatalf("Inline data over the limit must be rejected: %+v", resp)
+	}
+}
+
+func tarArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	return buf.String()
+}
+
+func TestJudge_Package(t *testing.T) {
+	storage := testutil.Storage{"p.tar": tarArchive(t, map[string]string{
+		"manifest.json": `{"time_limit": 500, "tests": [{"input": "1.in", "expected": "1.out", "group": "a"}, {"id": 2, "input": "2.in"}], "groups": [{"name": "a"}]}`,
+		"1.in":          "1\n",
+		"1.out":         "1\n",
+		"2.in":          "2\n",
+	})}
+	cache, err := problems.NewCache(problems.CacheConfig{Dir: t.TempDir()}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := NewJudge(Config{}, &testutil.EchoRunner{}, noStorage{}, cache)
+
+	task := &models.AttemptRequest{Id: 1, Package: "p.tar", PackageVersion: "1"}
+	resp := j.Process(context.Background(), task, nil)
+	if resp.Status != models.AttemptStatusSuccessful || len(resp.Tests) != 2 {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if task.Timeout != 500 {
+		t.Errorf("Time limit is not taken from the package: %d", task.Timeout)
+	}
+	if resp.Tests[0].Group != "a" || resp.Tests[0].Matched == nil || !*resp.Tests[0].Matched || resp.Tests[1].CaseId != 2 || resp.Tests[1].Output != "2\n" {
+		t.Fatalf("Unexpected tests: %+v", resp.Tests)
+	}
+	if len(resp.Groups) != 1 || resp.Groups[0] != (models.GroupResult{Name: "a", Passed: true}) {
+		t.Errorf("Unexpected groups: %+v", resp.Groups)
+	}
+
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, Package: "p.tar", PackageVersion: "1", TestCases: []models.TestCase{{Id: 2}}}, nil)
+	if len(resp.Tests) != 1 || resp.Tests[0].Output != "2\n" {
+		t.Fatalf("Selected test is not taken from the package: %+v", resp)
+	}
+
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 3, Package: "missing.tar"}, nil)
+	if resp.Status != models.AttemptStatusInternalError {
+		t.Fatalf("Missing package must fail: %+v", resp)
+	}
+}
+
+type filesRunner struct {
+	files       []dto.File
+	attachments []dto.File
+	memoryLimit int
+}
+
+func (r *filesRunner) Run(ctx context.Context, req *dto.RunRequest) (*dto.RunResult, error) {
+	r.files = req.Files
+	r.attachments = req.Attachments
+	r.memoryLimit = req.MemoryLimit
+	return &dto.RunResult{Status: models.AttemptStatusSuccessful}, nil
+}
+
+func TestJudge_SubmissionFiles(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, _ := zw.Create("src/util.py")
+	w.Write([]byte("X = 1"))
+	zw.Close()
+
+	runner := &filesRunner{}
+	j := NewJudge(Config{}, runner, noStorage{}, nil)
+	resp := j.Process(context.Background(), &models.AttemptRequest{
+		Id:      1,
+		Files:   []models.SourceFile{{Path: "lib.py", Content: "Y = 2"}},
+		Archive: buf.Bytes(),
+	}, nil)
+	if resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if len(runner.files) != 2 || runner.files[0] != (dto.File{Path: "lib.py", Content: "Y = 2"}) || runner.files[1] != (dto.File{Path: "src/util.py", Content: "X = 1"}) {
+		t.Fatalf("Unexpected files: %+v", runner.files)
+	}
+
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, Archive: []byte("not a zip")}, nil)
+	if resp.Status != models.AttemptStatusInternalError || resp.Error == "" {
+		t.Fatalf("Invalid archive must fail with error: %+v", resp)
+	}
+}
+
+func TestJudge_Attachments(t *testing.T) {
+	storage := testutil.Storage{
+		"grader.h": "int solve();",
+		"p.tar": tarArchive(t, map[string]string{
+			"manifest.json": `{"tests": [{"input": "1.in"}], "attachments": [{"path": "data/words.txt", "file": "words.txt"}]}`,
+			"1.in":          "",
+			"words.txt":     "a b",
+		}),
+	}
+	cache, err := problems.NewCache(problems.CacheConfig{Dir: t.TempDir()}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &filesRunner{}
+	j := NewJudge(Config{}, runner, storage, cache)
+	resp := j.Process(context.Background(), &models.AttemptRequest{
+		Id:          1,
+		Package:     "p.tar",
+		Attachments: []models.Attachment{{Path: "grader.h", FileName: "grader.h"}},
+	}, nil)
+	if resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if len(runner.attachments) != 2 || runner.attachments[0] != (dto.File{Path: "data/words.txt", Content: "a b"}) ||
+		runner.attachments[1] != (dto.File{Path: "grader.h", Content: "int solve();"}) {
+		t.Fatalf("Unexpected attachments: %+v", runner.attachments)
+	}
+
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, Attachments: []models.Attachment{{Path: "x.h", FileName: "missing"}}}, nil)
+	if resp.Status != models.AttemptStatusInternalError {
+		t.Fatalf("Missing attachment must fail: %+v", resp)
+	}
+}
+
+func TestJudge_MemoryLimit(t *testing.T) {
+	storage := testutil.Storage{"p.tar": tarArchive(t, map[string]string{
+		"manifest.json": `{"memory_limit": 268435456, "tests": [{"input": "1.in"}]}`,
+		"1.in":          "",
+	})}
+	cache, err := problems.NewCache(problems.CacheConfig{Dir: t.TempDir()}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &filesRunner{}
+	j := NewJudge(Config{}, runner, storage, cache)
+
+	j.Process(context.Background(), &models.AttemptRequest{Id: 1, MemoryLimit: 64 << 20}, nil)
+	if runner.memoryLimit != 64<<20 {
+		t.Errorf("Memory limit of the request is not passed: %d", runner.memoryLimit)
+	}
+	j.Process(context.Background(), &models.AttemptRequest{Id: 2, Package: "p.tar"}, nil)
+	if runner.memoryLimit != 256<<20 {
+		t.Errorf("Memory limit of the package is not passed: %d", runner.memoryLimit)
+	}
+}
+
+// blockingStorage calls stop and waits for the attempt context to end
+type blockingStorage struct {
+	stop func()
+}
+
+func (s blockingStorage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
+	if s.stop != nil {
+		s.stop()
+	}
+	<-ctx.Done()
+	// Like storage clients that don't wrap the context error
+	return nil, errors.New("connection closed")
+}
+
+func TestJudge_InterruptedLoading(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := NewJudge(Config{}, &testutil.EchoRunner{}, blockingStorage{stop: cancel}, nil)
+	resp := j.Process(ctx, &models.AttemptRequest{Id: 1, TestCases: []models.TestCase{{Id: 1, InputFileName: "1.in"}}}, nil)
+	if resp.Status != models.AttemptStatusCancelled {
+		t.Fatalf("Cancelled loading must cancel the attempt: %+v", resp)
+	}
+
+	j = NewJudge(Config{AttemptTimeout: 10 * time.Millisecond}, &testutil.EchoRunner{}, blockingStorage{}, nil)
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, VerificationFileName: "verifier.py"}, nil)
+	if resp.Status != models.AttemptStatusInternalError || !strings.Contains(resp.Error, "total time limit") {
+		t.Fatalf("Loading past the deadline must hit the total time limit: %+v", resp)
+	}
+}
+
+func TestJudge_InterruptedPackage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cache, err := problems.NewCache(problems.CacheConfig{Dir: t.TempDir()}, blockingStorage{stop: cancel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := NewJudge(Config{}, &testutil.EchoRunner{}, noStorage{}, cache)
+	resp := j.Process(ctx, &models.AttemptRequest{Id: 1, Package: "p.tar"}, nil)
+	if resp.Status != models.AttemptStatusCancelled {
+		t.Fatalf("Cancelled package loading must cancel the attempt: %+v", resp)
+	}
+
+	cache, err = problems.NewCache(problems.CacheConfig{Dir: t.TempDir()}, blockingStorage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j = NewJudge(Config{AttemptTimeout: 10 * time.Millisecond}, &testutil.EchoRunner{}, noStorage{}, cache)
+	resp = j.Process(context.Background(), &models.AttemptRequest{Id: 2, Package: "p.tar"}, nil)
+	if resp.Status != models.AttemptStatusInternalError || !strings.Contains(resp.Error, "total time limit") {
+		t.Fatalf("Package loading past the deadline must hit the total time limit: %+v", resp)
+	}
+}
+
+type resultRunner struct {
+	result *dto.RunResult
+}
+
+func (r resultRunner) Run(ctx context.Context, req *dto.RunRequest) (*dto.RunResult, error) {
+	return r.result, nil
+}
+
+func TestJudge_ErrorOnlyForBuild(t *testing.T) {
+	j := NewJudge(Config{}, resultRunner{&dto.RunResult{Status: models.AttemptStatusRunFailed, Error: "hidden input"}}, noStorage{}, nil)
+	if resp := j.Process(context.Background(), &models.AttemptRequest{Id: 1}, nil); resp.Error != "" {
+		t.Fatalf("Stderr of the solution must not be returned: %+v", resp)
+	}
+	j = NewJudge(Config{}, resultRunner{&dto.RunResult{Status: models.AttemptStatusBuildFailed, Error: "compiler exited with code 1", CompileOutput: "main.c:1: error"}}, noStorage{}, nil)
+	if resp := j.Process(context.Background(), &models.AttemptRequest{Id: 2}, nil); resp.Error != "compiler exited with code 1" || resp.CompileOutput != "main.c:1: error" {
+		t.Fatalf("Unexpected build failure response: %+v", resp)
+	}
+}
+
+func TestJudge_DefaultMemoryLimit(t *testing.T) {
+	runner := &filesRunner{}
+	j := NewJudge(Config{DefaultMemoryLimit: 256 << 20, MinMemoryLimit: 32 << 20}, runner, noStorage{}, nil)
+	j.Process(context.Background(), &models.AttemptRequest{Id: 1}, nil)
+	if runner.memoryLimit != 256<<20 {
+		t.Errorf("Default memory limit is not applied: %d", runner.memoryLimit)
+	}
+	j.Process(context.Background(), &models.AttemptRequest{Id: 2, MemoryLimit: 1000000}, nil)
+	if runner.memoryLimit != 32<<20 {
+		t.Errorf("Memory limit below the floor is not raised: %d", runner.memoryLimit)
+	}
+}
+
+// concurrentRunner records how many attempts run at once
+type concurrentRunner struct {
+	mu           sync.Mutex
+	running, max int
+}
+
+func (r *concurrentRunner) Run(ctx context.Context, req *dto.RunRequest) (*dto.RunResult, error) {
+	r.mu.Lock()
+	r.running++
+	r.max = max(r.max, r.running)
+	r.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+	return &dto.RunResult{Status: models.AttemptStatusSuccessful}, nil
+}
+
+func TestJudge_Workers(t *testing.T) {
+	runner := &concurrentRunner{}
+	j := NewJudge(Config{Workers: 2}, runner, noStorage{}, nil)
+	wg := sync.WaitGroup{}
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			j.Process(context.Background(), &models.AttemptRequest{Id: int64(i)}, nil)
+		}()
+	}
+	wg.Wait()
+	if runner.max != 2 {
+		t.Errorf("Unexpected max attempts at once: %d", runner.max)
+	}
+
+	// The only slot is taken, so the second attempt is cancelled while it waits
+	blocked := NewJudge(Config{Workers: 1}, &testutil.EchoRunner{Delay: time.Second}, noStorage{}, nil)
+	go blocked.Process(context.Background(), &models.AttemptRequest{Id: 1, TestCases: []models.TestCase{{Id: 1, Input: ptr("1")}}}, nil)
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if resp := blocked.Process(ctx, &models.AttemptRequest{Id: 2}, nil); resp.Status != models.AttemptStatusCancelled {
+		t.Errorf("Attempt waiting for a worker must be cancelled, got %+v", resp)
+	}
+}
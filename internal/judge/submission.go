@@ -0,0 +1,55 @@
+package judge
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+// Max unpacked size of a submission archive, the working directory is 32MB
+const maxArchiveSize = 16 * 1024 * 1024
+
+// submissionFiles returns source files of the attempt including files from its archive.
+// Paths are validated by the runner.
+func submissionFiles(task *models.AttemptRequest) ([]dto.File, error) {
+	files := make([]dto.File, 0, len(task.Files))
+	for _, f := range task.Files {
+		files = append(files, dto.File{Path: f.Path, Content: f.Content})
+	}
+	if len(task.Archive) == 0 {
+		return files, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(task.Archive), int64(len(task.Archive)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	var size int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			return nil, fmt.Errorf("unsupported file type of %s in archive", f.Name)
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize-size+1))
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		size += int64(len(data))
+		if size > maxArchiveSize {
+			return nil, fmt.Errorf("archive is larger than %d bytes", maxArchiveSize)
+		}
+		files = append(files, dto.File{Path: f.Name, Content: string(data)})
+	}
+	return files, nil
+}
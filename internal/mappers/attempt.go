@@ -1,16 +1,23 @@
 package mappers
 
 import (
+	"strings"
+
 	"github.com/cutekitek/rankode-runner/internal/repository/dto"
 	"github.com/cutekitek/rankode-runner/internal/repository/models"
 )
 
 func RunResultToAttemptResult(req *models.AttemptRequest, result *dto.RunResult) *models.AttemptResponse {
 	resp := &models.AttemptResponse{
-		Id:          req.Id,
-		Status:      result.Status,
-		MemoryUsage: int64(result.MemoryUsage),
-		Tests:       make([]models.TestStatus, 0, len(result.Output)),
+		Id:            req.Id,
+		Status:        result.Status,
+		CompileOutput: result.CompileOutput,
+		MemoryUsage:   int64(result.MemoryUsage),
+		Tests:         make([]models.TestStatus, 0, len(result.Output)),
+	}
+	// Other errors hold stderr of the solution, which may print hidden tests
+	if result.Status == models.AttemptStatusBuildFailed {
+		resp.Error = result.Error
 	}
 	for i, out := range result.Output {
 		caseId := int64(0)
@@ -23,7 +30,45 @@ func RunResultToAttemptResult(req *models.AttemptRequest, result *dto.RunResult)
 			Output:        out.Output,
 			ExecutionTime: out.ExecutionTime,
 		}
+		status.Matched = out.Matched
+		status.CheckerComment = out.CheckerComment
+		if i < len(req.TestCases) {
+			status.Group = req.TestCases[i].Group
+			if status.Matched == nil && req.TestCases[i].Expected != nil {
+				matched := OutputMatches(out.Output, *req.TestCases[i].Expected)
+				status.Matched = &matched
+			}
+		}
 		resp.Tests = append(resp.Tests, status)
 	}
 	return resp
 }
+
+// OutputMatches compares outputs ignoring trailing whitespace of lines and trailing empty lines
+func OutputMatches(output, expected string) bool {
+	return normalizeOutput(output) == normalizeOutput(expected)
+}
+
+func normalizeOutput(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func RunEventToAttemptEvent(req *models.AttemptRequest, event dto.RunEvent) *models.AttemptEvent {
+	res := &models.AttemptEvent{
+		Id:   req.Id,
+		Type: event.Type,
+	}
+	if event.Type == models.AttemptEventTestStarted || event.Type == models.AttemptEventTestFinished {
+		res.Test = event.Test
+		res.Status = event.Status
+		res.ExecutionTime = event.ExecutionTime
+		if event.Test < len(req.TestCases) {
+			res.CaseId = req.TestCases[event.Test].Id
+		}
+	}
+	return res
+}
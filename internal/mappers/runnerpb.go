@@ -0,0 +1,83 @@
+package mappers
+
+import (
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/pkg/runnerpb"
+)
+
+func AttemptRequestFromProto(req *runnerpb.AttemptRequest) *models.AttemptRequest {
+	task := &models.AttemptRequest{
+		Id:                   req.GetId(),
+		Language:             req.GetLanguage(),
+		Code:                 req.GetCode(),
+		MemoryLimit:          req.GetMemoryLimit(),
+		Timeout:              req.GetTimeout(),
+		MaxOutputSize:        req.GetMaxOutputSize(),
+		Priority:             uint8(min(req.GetPriority(), 255)),
+		TestCases:            make([]models.TestCase, 0, len(req.GetTestCases())),
+		VerificationFileName: req.GetVerificationFile(),
+		Package:              req.GetPackage(),
+		PackageVersion:       req.GetPackageVersion(),
+		Archive:              req.GetArchive(),
+		InputFile:            req.GetInputFile(),
+		OutputFile:           req.GetOutputFile(),
+	}
+	for _, f := range req.GetFiles() {
+		task.Files = append(task.Files, models.SourceFile{Path: f.GetPath(), Content: f.GetContent()})
+	}
+	for _, a := range req.GetAttachments() {
+		task.Attachments = append(task.Attachments, models.Attachment{Path: a.GetPath(), FileName: a.GetFile()})
+	}
+	for _, test := range req.GetTestCases() {
+		task.TestCases = append(task.TestCases, models.TestCase{
+			Id:            test.GetId(),
+			Order:         test.GetOrder(),
+			InputFileName: test.GetInputFile(),
+			Input:         test.Input,
+			Expected:      test.Expected,
+		})
+	}
+	return task
+}
+
+func AttemptResponseToProto(resp *models.AttemptResponse) *runnerpb.AttemptResponse {
+	res := &runnerpb.AttemptResponse{
+		Id:            resp.Id,
+		Status:        runnerpb.AttemptStatus(resp.Status),
+		Error:         resp.Error,
+		CompileOutput: resp.CompileOutput,
+		MemoryUsage:   resp.MemoryUsage,
+		Tests:         make([]*runnerpb.TestStatus, 0, len(resp.Tests)),
+	}
+	for _, test := range resp.Tests {
+		res.Tests = append(res.Tests, &runnerpb.TestStatus{
+			TestId:         test.CaseId,
+			Status:         runnerpb.TestCaseStatus(test.Status),
+			Output:         test.Output,
+			ExecutionTime:  test.ExecutionTime,
+			Matched:        test.Matched,
+			CheckerComment: test.CheckerComment,
+			Group:          test.Group,
+		})
+	}
+	for _, group := range resp.Groups {
+		res.Groups = append(res.Groups, &runnerpb.GroupResult{
+			Name:   group.Name,
+			Points: group.Points,
+			Score:  group.Score,
+			Passed: group.Passed,
+		})
+	}
+	return res
+}
+
+func AttemptEventToProto(event *models.AttemptEvent) *runnerpb.AttemptEvent {
+	return &runnerpb.AttemptEvent{
+		Id:            event.Id,
+		Type:          runnerpb.AttemptEventType(event.Type),
+		TestId:        event.CaseId,
+		Test:          int32(event.Test),
+		Status:        runnerpb.TestCaseStatus(event.Status),
+		ExecutionTime: event.ExecutionTime,
+	}
+}
@@ -0,0 +1,209 @@
+package natsjs
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/worker"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/pkg/errors"
+)
+
+type Config struct {
+	URL string
+	// Stream with attempt requests, created if not exists
+	Stream string
+	// Subject of attempt requests in the stream
+	Subject string
+	// Durable consumer name shared by all runners
+	Durable string
+	// Subject for attempt responses
+	ResponseSubject string
+	// Prefix of subjects for progress events, the attempt id is appended.
+	// Events are not published if empty
+	EventsSubject string
+	// Subject with cancel requests, disabled if empty
+	CancelSubject string
+	// Attempt is redelivered if not acknowledged during this time. While the attempt
+	// is running the runner extends it
+	AckWait time.Duration
+	// Attempt is dropped after so many deliveries, e.g. if it crashes runners
+	MaxDeliver int
+}
+
+type message struct {
+	msg  jetstream.Msg
+	stop chan struct{}
+}
+
+// Source pulls attempts from a JetStream durable consumer with explicit acks.
+// It is both job source and result sink for the worker pool.
+type Source struct {
+	cfg       Config
+	conn      *nats.Conn
+	consumer  jetstream.Consumer
+	cancelSub *nats.Subscription
+	cancelled chan int64
+	ctx       context.Context
+	stop      context.CancelFunc
+}
+
+func NewSource(cfg Config) *Source {
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = 30 * time.Second
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &Source{cfg: cfg, cancelled: make(chan int64, 64), ctx: ctx, stop: stop}
+}
+
+func (s *Source) Start() error {
+	conn, err := nats.Connect(s.cfg.URL, nats.MaxReconnects(-1))
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to nats")
+	}
+	s.conn = conn
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+	stream, err := js.Stream(ctx, s.cfg.Stream)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		stream, err = js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     s.cfg.Stream,
+			Subjects: []string{s.cfg.Subject},
+			// Attempt is removed from the stream once a runner acknowledged it
+			Retention: jetstream.WorkQueuePolicy,
+		})
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get stream")
+	}
+	s.consumer, err = stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       s.cfg.Durable,
+		FilterSubject: s.cfg.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       s.cfg.AckWait,
+		MaxDeliver:    s.cfg.MaxDeliver,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create consumer")
+	}
+
+	if s.cfg.CancelSubject != "" {
+		s.cancelSub, err = conn.Subscribe(s.cfg.CancelSubject, s.onCancel)
+		if err != nil {
+			return errors.Wrap(err, "failed to subscribe to cancel subject")
+		}
+	}
+	return nil
+}
+
+func (s *Source) onCancel(msg *nats.Msg) {
+	var req models.CancelRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		slog.Error("invalid cancel message", "message", string(msg.Data), "error", err)
+		return
+	}
+	select {
+	case s.cancelled <- req.Id:
+	default:
+		slog.Warn("cancel request dropped, workers are not listening", "id", req.Id)
+	}
+}
+
+// Next fetches one attempt when a worker is free, so attempts not taken yet stay
+// in the stream for other runners.
+func (s *Source) Next() (*worker.Job, bool) {
+	for s.ctx.Err() == nil {
+		batch, err := s.consumer.Fetch(1, jetstream.FetchContext(s.ctx))
+		if err != nil {
+			if s.ctx.Err() == nil {
+				slog.Error("failed to fetch attempt", "error", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+		for msg := range batch.Messages() {
+			var task models.AttemptRequest
+			if err := json.Unmarshal(msg.Data(), &task); err != nil {
+				slog.Error("invalid task message", "message", string(msg.Data()), "error", err)
+				msg.Term()
+				continue
+			}
+			m := &message{msg: msg, stop: make(chan struct{})}
+			go s.keepInProgress(m)
+			return &worker.Job{Request: &task, Data: m}, true
+		}
+		if err := batch.Error(); err != nil && s.ctx.Err() == nil && !errors.Is(err, nats.ErrTimeout) {
+			slog.Error("failed to fetch attempt", "error", err)
+		}
+	}
+	return nil, false
+}
+
+// keepInProgress extends ack wait while the attempt is judged, long attempts are
+// not redelivered to other runners.
+func (s *Source) keepInProgress(m *message) {
+	ticker := time.NewTicker(s.cfg.AckWait / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.msg.InProgress(); err != nil {
+				slog.Warn("failed to extend attempt ack wait", "error", err)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (s *Source) Ack(job *worker.Job) {
+	m := job.Data.(*message)
+	close(m.stop)
+	if err := m.msg.Ack(); err != nil {
+		slog.Error("failed to ack task", "id", job.Request.Id, "error", err)
+	}
+}
+
+func (s *Source) Stop() {
+	s.stop()
+}
+
+func (s *Source) Close() {
+	if s.cancelSub != nil {
+		s.cancelSub.Unsubscribe()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
+
+func (s *Source) Cancelled() <-chan int64 {
+	return s.cancelled
+}
+
+func (s *Source) SendResult(job *worker.Job, resp *models.AttemptResponse) {
+	body, _ := json.Marshal(resp)
+	if err := s.conn.Publish(s.cfg.ResponseSubject, body); err != nil {
+		slog.Error("failed to send response", "error", err)
+	}
+}
+
+func (s *Source) SendEvent(job *worker.Job, event *models.AttemptEvent) {
+	if s.cfg.EventsSubject == "" {
+		return
+	}
+	body, _ := json.Marshal(event)
+	if err := s.conn.Publish(s.cfg.EventsSubject+"."+strconv.FormatInt(event.Id, 10), body); err != nil {
+		slog.Warn("failed to send attempt event", "id", event.Id, "error", err)
+	}
+}
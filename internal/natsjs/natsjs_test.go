@@ -0,0 +1,108 @@
+package natsjs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+	"github.com/cutekitek/rankode-runner/internal/worker"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func startServer(t *testing.T) *server.Server {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func TestSource_AcknowledgesOnce(t *testing.T) {
+	srv := startServer(t)
+	cfg := Config{
+		URL:             srv.ClientURL(),
+		Stream:          "RANKODE",
+		Subject:         "rankode.req",
+		Durable:         "rankode-runner",
+		ResponseSubject: "rankode.resp",
+		EventsSubject:   "rankode.events",
+		AckWait:         time.Second,
+		MaxDeliver:      3,
+	}
+	source := NewSource(cfg)
+	if err := source.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	// Longer than ack wait, the attempt must not be redelivered
+	runner := &testutil.EchoRunner{Delay: 1500 * time.Millisecond}
+	pool := worker.NewPool(2, judge.NewJudge(judge.Config{}, runner, testutil.Storage{"in1": "1"}, nil), source, source)
+	pool.Start()
+	defer func() {
+		pool.Close()
+		source.Close()
+	}()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	responses := make(chan *nats.Msg, 4)
+	if _, err := nc.ChanSubscribe(cfg.ResponseSubject, responses); err != nil {
+		t.Fatal(err)
+	}
+	events, err := nc.SubscribeSync(cfg.EventsSubject + ".7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(models.AttemptRequest{Id: 7, TestCases: []models.TestCase{{Id: 70, InputFileName: "in1"}}})
+	if _, err := js.Publish(context.Background(), cfg.Subject, body); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var resp models.AttemptResponse
+	select {
+	case msg := <-responses:
+		if err := json.Unmarshal(msg.Data, &resp); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("no response received")
+	}
+	if resp.Id != 7 || resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if _, err := events.NextMsg(time.Second); err != nil {
+		t.Fatalf("No progress event received: %v", err)
+	}
+
+	select {
+	case msg := <-responses:
+		t.Fatalf("Attempt was judged twice: %s", msg.Data)
+	case <-time.After(2 * time.Second):
+	}
+}
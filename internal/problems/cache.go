@@ -0,0 +1,271 @@
+package problems
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type FileStorage interface {
+	GetFile(ctx context.Context, filename string) (io.Reader, error)
+}
+
+type CacheConfig struct {
+	// Directory with unpacked packages, kept between restarts
+	Dir string
+	// Max total size of unpacked files of one package
+	MaxSize int64
+}
+
+type entry struct {
+	ready chan struct{}
+	pkg   *Package
+	err   error
+}
+
+// Cache downloads problem packages from storage and unpacks them into
+// <dir>/<name>/<version>. An unpacked version is never downloaded again, so a
+// changed package must get a new version.
+type Cache struct {
+	cfg     CacheConfig
+	storage FileStorage
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func NewCache(cfg CacheConfig, storage FileStorage) (*Cache, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create package cache directory: %w", err)
+	}
+	return &Cache{cfg: cfg, storage: storage, entries: make(map[string]*entry)}, nil
+}
+
+// Get returns the unpacked package, downloading it if needed. Concurrent calls
+// for the same package wait for a single download.
+func (c *Cache) Get(ctx context.Context, name, version string) (*Package, error) {
+	if version == "" {
+		version = "_"
+	}
+	dir := filepath.Join(c.cfg.Dir, url.PathEscape(name), url.PathEscape(version))
+
+	c.mu.Lock()
+	e, ok := c.entries[dir]
+	if !ok {
+		e = &entry{ready: make(chan struct{})}
+		c.entries[dir] = e
+	}
+	c.mu.Unlock()
+
+	if ok {
+		select {
+		case <-e.ready:
+			return e.pkg, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	e.pkg, e.err = c.load(ctx, name, version, dir)
+	if e.err != nil {
+		// Let the next attempt retry
+		c.mu.Lock()
+		delete(c.entries, dir)
+		c.mu.Unlock()
+	}
+	close(e.ready)
+	return e.pkg, e.err
+}
+
+func (c *Cache) load(ctx context.Context, name, version, dir string) (*Package, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return openPackage(name, version, dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".unpack-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := c.download(ctx, name, tmp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack package %s", name)
+	}
+	// Check the manifest before the package gets into the cache
+	pkg, err := openPackage(name, version, tmp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "package %s", name)
+	}
+	pkg.root.Close()
+	if err := os.Rename(tmp, dir); err != nil {
+		// Another runner sharing the directory may have unpacked it first
+		if _, statErr := os.Stat(dir); statErr != nil {
+			return nil, err
+		}
+	}
+	return openPackage(name, version, dir)
+}
+
+func (c *Cache) download(ctx context.Context, name, dst string) error {
+	reader, err := c.storage.GetFile(ctx, name)
+	if err != nil {
+		return err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	root, err := os.OpenRoot(dst)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	u := &unpacker{root: root, limit: c.cfg.MaxSize}
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		// zip needs random access, keep the archive next to the unpacked files
+		archive, err := os.CreateTemp(filepath.Dir(dst), ".download-")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(archive.Name())
+		defer archive.Close()
+		size, err := io.Copy(archive, reader)
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(archive, size)
+		if err != nil {
+			return err
+		}
+		return u.unzip(zr)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return u.untar(tar.NewReader(gz))
+	case strings.HasSuffix(lower, ".tar"):
+		return u.untar(tar.NewReader(reader))
+	default:
+		return fmt.Errorf("unknown archive format, expected .zip, .tar, .tar.gz or .tgz")
+	}
+}
+
+type unpacker struct {
+	root *os.Root
+	// zero means no limit
+	limit   int64
+	written int64
+}
+
+func (u *unpacker) unzip(zr *zip.Reader) error {
+	for _, f := range zr.File {
+		switch {
+		case f.FileInfo().IsDir():
+			if err := u.mkdir(f.Name); err != nil {
+				return err
+			}
+		case f.Mode().IsRegular():
+			r, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = u.write(f.Name, r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported file type of %s", f.Name)
+		}
+	}
+	return nil
+}
+
+func (u *unpacker) untar(tr *tar.Reader) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := u.mkdir(hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := u.write(hdr.Name, tr); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+		default:
+			return fmt.Errorf("unsupported file type of %s", hdr.Name)
+		}
+	}
+}
+
+func (u *unpacker) mkdir(name string) error {
+	name, err := localPath(name)
+	if err != nil || name == "." {
+		return err
+	}
+	return u.root.MkdirAll(name, 0o755)
+}
+
+func (u *unpacker) write(name string, r io.Reader) error {
+	name, err := localPath(name)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(name); dir != "." {
+		if err := u.root.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := u.root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if u.limit == 0 {
+		_, err = io.Copy(f, r)
+		return err
+	}
+	n, err := io.CopyN(f, r, u.limit-u.written+1)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	u.written += n
+	if u.written > u.limit {
+		return fmt.Errorf("package is larger than %d bytes", u.limit)
+	}
+	return nil
+}
+
+// localPath rejects absolute names and names leaving the package
+func localPath(name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimSuffix(name, "/"))
+	if !filepath.IsLocal(name) {
+		return "", &fs.PathError{Op: "unpack", Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Clean(name), nil
+}
@@ -0,0 +1,152 @@
+package problems
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+)
+
+const testManifest = `{
+	"time_limit": 1000,
+	"checker": {"language": "c++", "file": "check.cpp"},
+	"groups": [{"name": "samples"}, {"name": "main", "points": 100, "dependencies": ["samples"]}],
+	"tests": [
+		{"input": "tests/01", "expected": "tests/01.a", "group": "samples"},
+		{"id": 5, "input": "tests/02", "group": "main"}
+	]
+}`
+
+var testFiles = map[string]string{
+	"manifest.json": testManifest,
+	"check.cpp":     "int main() {}",
+	"tests/01":      "1 2\n",
+	"tests/01.a":    "3\n",
+	"tests/02":      "5 5\n",
+}
+
+type countingStorage struct {
+	files map[string][]byte
+	calls atomic.Int32
+}
+
+func (s *countingStorage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
+	s.calls.Add(1)
+	return bytes.NewReader(s.files[filename]), nil
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tgzArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestCache_Get(t *testing.T) {
+	for name, archive := range map[string][]byte{
+		"problems/1.zip":    zipArchive(t, testFiles),
+		"problems/1.tar.gz": tgzArchive(t, testFiles),
+	} {
+		t.Run(name, func(t *testing.T) {
+			storage := &countingStorage{files: map[string][]byte{name: archive}}
+			dir := t.TempDir()
+			cache, err := NewCache(CacheConfig{Dir: dir}, storage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkg, err := cache.Get(context.Background(), name, "v1")
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			m := pkg.Manifest
+			if m.TimeLimit != 1000 || m.Checker.Language != "c++" || len(m.Tests) != 2 || m.Tests[0].Id != 1 || m.Tests[1].Id != 5 {
+				t.Fatalf("Unexpected manifest: %+v", m)
+			}
+			if data, err := pkg.ReadFile("tests/01.a"); err != nil || data != "3\n" {
+				t.Fatalf("ReadFile = %q, %v", data, err)
+			}
+			if _, err := pkg.ReadFile("../../x"); err == nil {
+				t.Fatal("ReadFile must not leave the package")
+			}
+
+			if _, err := cache.Get(context.Background(), name, "v1"); err != nil {
+				t.Fatal(err)
+			}
+			// Unpacked version is reused after restart
+			cache, _ = NewCache(CacheConfig{Dir: dir}, storage)
+			if _, err := cache.Get(context.Background(), name, "v1"); err != nil {
+				t.Fatal(err)
+			}
+			if calls := storage.calls.Load(); calls != 1 {
+				t.Fatalf("Package downloaded %d times", calls)
+			}
+
+			if _, err := cache.Get(context.Background(), name, "v2"); err != nil {
+				t.Fatal(err)
+			}
+			if calls := storage.calls.Load(); calls != 2 {
+				t.Fatalf("New version must be downloaded, downloads: %d", calls)
+			}
+		})
+	}
+}
+
+func TestCache_InvalidPackages(t *testing.T) {
+	withFile := func(name, content string) map[string]string {
+		files := map[string]string{name: content}
+		for k, v := range testFiles {
+			if _, ok := files[k]; !ok {
+				files[k] = v
+			}
+		}
+		return files
+	}
+	tests := map[string][]byte{
+		"slip.zip":        zipArchive(t, withFile("../evil", "x")),
+		"absolute.tar.gz": tgzArchive(t, withFile("/evil", "x")),
+		"no-manifest.zip": zipArchive(t, map[string]string{"tests/01": "1"}),
+		"bad-group.zip":   zipArchive(t, withFile("manifest.json", `{"tests": [{"input": "tests/01", "group": "x"}]}`)),
+		"large.zip":       zipArchive(t, withFile("big", string(make([]byte, 4096)))),
+		"unknown.rar":     {},
+	}
+	storage := &countingStorage{files: tests}
+	cache, err := NewCache(CacheConfig{Dir: t.TempDir(), MaxSize: 1024}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range tests {
+		if _, err := cache.Get(context.Background(), name, "v1"); err == nil {
+			t.Errorf("Get(%s) must fail", name)
+		}
+	}
+}
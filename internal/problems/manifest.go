@@ -0,0 +1,194 @@
+package problems
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ManifestFile is the name of the manifest in the root of a package
+const ManifestFile = "manifest.json"
+
+// Manifest describes contents of a problem package, file paths are slash
+// separated and relative to the package root.
+type Manifest struct {
+	// Milliseconds per test
+	TimeLimit int64 `json:"time_limit"`
+	// Bytes
+	MemoryLimit   int64    `json:"memory_limit"`
+	MaxOutputSize int64    `json:"max_output_size"`
+	Checker       *Checker `json:"checker,omitempty"`
+	Groups        []Group  `json:"groups,omitempty"`
+	Tests         []Test   `json:"tests"`
+	// Files placed read-only next to the solution sources, e.g. grader.h
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Files the solution reads tests from and writes outputs to, stdin and stdout if empty
+	InputFile  string `json:"input_file,omitempty"`
+	OutputFile string `json:"output_file,omitempty"`
+}
+
+// Checker compares the output of a test with the expected one. It is run as
+// `checker input output answer` like testlib checkers, exit code 0 means accepted.
+type Checker struct {
+	// Runner language used to build and run the checker
+	Language string `json:"language"`
+	File     string `json:"file"`
+	// Files copied next to the checker source by base name, e.g. testlib.h
+	Files []string `json:"files,omitempty"`
+}
+
+type Attachment struct {
+	// Path relative to the working directory of the solution
+	Path string `json:"path"`
+	File string `json:"file"`
+}
+
+type Group struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	// Groups which must be passed for this group to count
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+type Test struct {
+	// Defaults to the position of the test starting from 1
+	Id       int64  `json:"id"`
+	Input    string `json:"input"`
+	Expected string `json:"expected,omitempty"`
+	Group    string `json:"group,omitempty"`
+}
+
+func (m *Manifest) validate() error {
+	if len(m.Tests) == 0 {
+		return fmt.Errorf("no tests")
+	}
+	groups := make(map[string]bool, len(m.Groups))
+	for _, g := range m.Groups {
+		if g.Name == "" {
+			return fmt.Errorf("group without name")
+		}
+		groups[g.Name] = true
+	}
+	for _, g := range m.Groups {
+		for _, dep := range g.Dependencies {
+			if !groups[dep] {
+				return fmt.Errorf("group %s depends on unknown group %s", g.Name, dep)
+			}
+		}
+	}
+	for i, t := range m.Tests {
+		if t.Input == "" {
+			return fmt.Errorf("test %d has no input", i+1)
+		}
+		if t.Group != "" && !groups[t.Group] {
+			return fmt.Errorf("test %d is in unknown group %s", i+1, t.Group)
+		}
+	}
+	if m.Checker != nil && (m.Checker.Language == "" || m.Checker.File == "") {
+		return fmt.Errorf("checker requires language and file")
+	}
+	for i, a := range m.Attachments {
+		if a.Path == "" || a.File == "" {
+			return fmt.Errorf("attachment %d requires path and file", i+1)
+		}
+	}
+	return nil
+}
+
+// Package is an unpacked problem package
+type Package struct {
+	Name     string
+	Version  string
+	Manifest Manifest
+	root     *os.Root
+}
+
+func openPackage(name, version, dir string) (*Package, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	m, err := readManifest(root)
+	if err == nil {
+		err = m.validate()
+	}
+	if err == nil {
+		err = checkFiles(root, m)
+	}
+	if err != nil {
+		root.Close()
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	p := &Package{Name: name, Version: version, Manifest: *m, root: root}
+	for i := range p.Manifest.Tests {
+		if p.Manifest.Tests[i].Id == 0 {
+			p.Manifest.Tests[i].Id = int64(i + 1)
+		}
+	}
+	return p, nil
+}
+
+// ReadFile reads a file of the package, names leaving the package are rejected
+func (p *Package) ReadFile(name string) (string, error) {
+	file, err := p.root.Open(filepath.FromSlash(name))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// readManifest reads manifest.json of the package or converts problem.xml of a Polygon package
+func readManifest(root *os.Root) (*Manifest, error) {
+	file, err := root.Open(ManifestFile)
+	if err == nil {
+		defer file.Close()
+		m := new(Manifest)
+		if err := json.NewDecoder(file).Decode(m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	file, err = root.Open(PolygonFile)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("neither %s nor %s found", ManifestFile, PolygonFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParsePolygon(file)
+}
+
+// checkFiles makes sure files referenced by the manifest exist
+func checkFiles(root *os.Root, m *Manifest) error {
+	var files []string
+	for _, t := range m.Tests {
+		files = append(files, t.Input)
+		if t.Expected != "" {
+			files = append(files, t.Expected)
+		}
+	}
+	if m.Checker != nil {
+		files = append(files, m.Checker.File)
+		files = append(files, m.Checker.Files...)
+	}
+	for _, a := range m.Attachments {
+		files = append(files, a.File)
+	}
+	for _, name := range files {
+		if _, err := root.Stat(filepath.FromSlash(name)); err != nil {
+			return fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+	return nil
+}
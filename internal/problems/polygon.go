@@ -0,0 +1,184 @@
+package problems
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// PolygonFile is the descriptor of packages built by Codeforces Polygon
+const PolygonFile = "problem.xml"
+
+type polygonProblem struct {
+	Judging struct {
+		InputFile  string           `xml:"input-file,attr"`
+		OutputFile string           `xml:"output-file,attr"`
+		Testsets   []polygonTestset `xml:"testset"`
+	} `xml:"judging"`
+	Files struct {
+		Resources []polygonFile `xml:"resources>file"`
+	} `xml:"files"`
+	Assets struct {
+		Checker *struct {
+			Type   string      `xml:"type,attr"`
+			Source polygonFile `xml:"source"`
+		} `xml:"checker"`
+		Interactor *struct {
+			Source polygonFile `xml:"source"`
+		} `xml:"interactor"`
+	} `xml:"assets"`
+}
+
+type polygonTestset struct {
+	Name          string `xml:"name,attr"`
+	TimeLimit     int64  `xml:"time-limit"`
+	MemoryLimit   int64  `xml:"memory-limit"`
+	TestCount     int    `xml:"test-count"`
+	InputPattern  string `xml:"input-path-pattern"`
+	AnswerPattern string `xml:"answer-path-pattern"`
+	Tests         []struct {
+		Group  string  `xml:"group,attr"`
+		Points float64 `xml:"points,attr"`
+	} `xml:"tests>test"`
+	Groups []struct {
+		Name         string   `xml:"name,attr"`
+		Points       *float64 `xml:"points,attr"`
+		Dependencies []struct {
+			Group string `xml:"group,attr"`
+		} `xml:"dependencies>dependency"`
+	} `xml:"groups>group"`
+}
+
+type polygonFile struct {
+	Path   string `xml:"path,attr"`
+	Type   string `xml:"type,attr"`
+	Assets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"assets>asset"`
+}
+
+// ParsePolygon translates problem.xml of a full Polygon package (with generated
+// tests and answers) into a manifest. Interactive problems are not supported.
+func ParsePolygon(r io.Reader) (*Manifest, error) {
+	var p polygonProblem
+	if err := xml.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid problem.xml: %w", err)
+	}
+	if p.Assets.Interactor != nil {
+		return nil, fmt.Errorf("interactive problems are not supported")
+	}
+	if len(p.Judging.Testsets) == 0 {
+		return nil, fmt.Errorf("no testsets")
+	}
+	testset := p.Judging.Testsets[0]
+	for _, ts := range p.Judging.Testsets {
+		if ts.Name == "tests" {
+			testset = ts
+		}
+	}
+
+	m := &Manifest{
+		TimeLimit:   testset.TimeLimit,
+		MemoryLimit: testset.MemoryLimit,
+		InputFile:   stdioFile(p.Judging.InputFile, "stdin"),
+		OutputFile:  stdioFile(p.Judging.OutputFile, "stdout"),
+	}
+
+	count := testset.TestCount
+	if count == 0 {
+		count = len(testset.Tests)
+	}
+	groupPoints := make(map[string]float64)
+	var groupOrder []string
+	for i := 1; i <= count; i++ {
+		test := Test{Id: int64(i), Input: fmt.Sprintf(testset.InputPattern, i)}
+		if testset.AnswerPattern != "" {
+			test.Expected = fmt.Sprintf(testset.AnswerPattern, i)
+		}
+		if i <= len(testset.Tests) {
+			t := testset.Tests[i-1]
+			test.Group = t.Group
+			if t.Group != "" {
+				if _, ok := groupPoints[t.Group]; !ok {
+					groupOrder = append(groupOrder, t.Group)
+				}
+				groupPoints[t.Group] += t.Points
+			}
+		}
+		m.Tests = append(m.Tests, test)
+	}
+
+	// Groups may be used by tests without being described
+	declared := make(map[string]bool)
+	for _, g := range testset.Groups {
+		group := Group{Name: g.Name, Points: groupPoints[g.Name]}
+		if g.Points != nil {
+			group.Points = *g.Points
+		}
+		for _, dep := range g.Dependencies {
+			group.Dependencies = append(group.Dependencies, dep.Group)
+		}
+		m.Groups = append(m.Groups, group)
+		declared[g.Name] = true
+	}
+	for _, name := range groupOrder {
+		if !declared[name] {
+			m.Groups = append(m.Groups, Group{Name: name, Points: groupPoints[name]})
+		}
+	}
+
+	// Graders compiled together with the solution
+	for _, f := range p.Files.Resources {
+		for _, a := range f.Assets {
+			if a.Name == "solution" {
+				m.Attachments = append(m.Attachments, Attachment{Path: path.Base(f.Path), File: f.Path})
+			}
+		}
+	}
+
+	if c := p.Assets.Checker; c != nil && c.Source.Path != "" {
+		lang, err := polygonLanguage(c.Source.Type)
+		if err != nil {
+			return nil, fmt.Errorf("checker: %w", err)
+		}
+		m.Checker = &Checker{Language: lang, File: c.Source.Path}
+		// testlib.h and other headers included by the checker
+		for _, f := range p.Files.Resources {
+			if strings.HasPrefix(f.Type, "h.") {
+				m.Checker.Files = append(m.Checker.Files, f.Path)
+			}
+		}
+	}
+	return m, nil
+}
+
+// stdioFile returns the file name of file I/O or empty string for standard streams
+func stdioFile(name, std string) string {
+	if name == std {
+		return ""
+	}
+	return name
+}
+
+// polygonLanguage maps Polygon source types, e.g. cpp.g++17, to runner languages
+func polygonLanguage(sourceType string) (string, error) {
+	switch kind, _, _ := strings.Cut(sourceType, "."); kind {
+	case "cpp":
+		return "c++", nil
+	case "c":
+		return "c", nil
+	case "java":
+		return "java", nil
+	case "python":
+		if sourceType == "python.3" {
+			return "python3", nil
+		}
+	case "go":
+		return "go", nil
+	case "js":
+		return "js", nil
+	}
+	return "", fmt.Errorf("unsupported source type %s", sourceType)
+}
@@ -0,0 +1,143 @@
+package problems
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testProblemXML = `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<problem revision="3" short-name="a-plus-b" url="https://polygon.codeforces.com/p/test/a-plus-b">
+    <names>
+        <name language="english" value="A+B"/>
+    </names>
+    <judging cpu-name="Intel(R) Core(TM) i3-8100 CPU @ 3.60GHz" cpu-speed="3600" input-file="" output-file="">
+        <testset name="tests">
+            <time-limit>2000</time-limit>
+            <memory-limit>268435456</memory-limit>
+            <test-count>3</test-count>
+            <input-path-pattern>tests/%02d</input-path-pattern>
+            <answer-path-pattern>tests/%02d.a</answer-path-pattern>
+            <tests>
+                <test method="manual" sample="true" group="0" points="0.0"/>
+                <test method="generated" cmd="gen 1" group="1" points="20.0"/>
+                <test method="generated" cmd="gen 2" group="1" points="30.0"/>
+            </tests>
+            <groups>
+                <group feedback-policy="complete" name="0" points-policy="each-test"/>
+                <group feedback-policy="icpc" name="1" points-policy="complete-group">
+                    <dependencies>
+                        <dependency group="0"/>
+                    </dependencies>
+                </group>
+            </groups>
+        </testset>
+    </judging>
+    <files>
+        <resources>
+            <file path="files/olymp.sty"/>
+            <file path="files/testlib.h" type="h.g++"/>
+            <file path="files/grader.cpp" type="cpp.g++17">
+                <assets>
+                    <asset name="solution"/>
+                </assets>
+            </file>
+        </resources>
+    </files>
+    <assets>
+        <checker name="std::ncmp.cpp" type="testlib">
+            <source path="files/check.cpp" type="cpp.g++17"/>
+            <binary path="check.exe" type="exe.win32"/>
+            <copy path="check.cpp"/>
+        </checker>
+        <solutions>
+            <solution tag="main">
+                <source path="solutions/sol.cpp" type="cpp.g++17"/>
+            </solution>
+        </solutions>
+    </assets>
+</problem>`
+
+func TestParsePolygon(t *testing.T) {
+	m, err := ParsePolygon(strings.NewReader(testProblemXML))
+	if err != nil {
+		t.Fatalf("ParsePolygon failed: %v", err)
+	}
+	if m.TimeLimit != 2000 || m.MemoryLimit != 268435456 || m.InputFile != "" || m.OutputFile != "" {
+		t.Errorf("Unexpected limits: %d, %d", m.TimeLimit, m.MemoryLimit)
+	}
+	if len(m.Tests) != 3 || m.Tests[1] != (Test{Id: 2, Input: "tests/02", Expected: "tests/02.a", Group: "1"}) {
+		t.Fatalf("Unexpected tests: %+v", m.Tests)
+	}
+	if len(m.Groups) != 2 || m.Groups[1].Name != "1" || m.Groups[1].Points != 50 || len(m.Groups[1].Dependencies) != 1 {
+		t.Fatalf("Unexpected groups: %+v", m.Groups)
+	}
+	if m.Checker == nil || m.Checker.Language != "c++" || m.Checker.File != "files/check.cpp" ||
+		len(m.Checker.Files) != 1 || m.Checker.Files[0] != "files/testlib.h" {
+		t.Fatalf("Unexpected checker: %+v", m.Checker)
+	}
+	if len(m.Attachments) != 1 || m.Attachments[0] != (Attachment{Path: "grader.cpp", File: "files/grader.cpp"}) {
+		t.Fatalf("Unexpected attachments: %+v", m.Attachments)
+	}
+}
+
+func TestParsePolygon_FileIO(t *testing.T) {
+	xml := strings.Replace(testProblemXML, `input-file=""`, `input-file="input.txt"`, 1)
+	xml = strings.Replace(xml, `output-file=""`, `output-file="stdout"`, 1)
+	m, err := ParsePolygon(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("ParsePolygon failed: %v", err)
+	}
+	if m.InputFile != "input.txt" || m.OutputFile != "" {
+		t.Fatalf("Unexpected I/O files: %q, %q", m.InputFile, m.OutputFile)
+	}
+}
+
+func TestParsePolygon_Unsupported(t *testing.T) {
+	tests := map[string]string{
+		"interactor": strings.Replace(testProblemXML, "<solutions>",
+			`<interactor><source path="files/interactor.cpp" type="cpp.g++17"/></interactor><solutions>`, 1),
+		"language": strings.Replace(testProblemXML, `path="files/check.cpp" type="cpp.g++17"`, `path="files/check.pas" type="pas.fpc"`, 1),
+	}
+	for name, xml := range tests {
+		if _, err := ParsePolygon(strings.NewReader(xml)); err == nil {
+			t.Errorf("%s: ParsePolygon must fail", name)
+		}
+	}
+}
+
+func TestCache_PolygonPackage(t *testing.T) {
+	files := map[string]string{
+		"problem.xml":       testProblemXML,
+		"files/check.cpp":   "#include \"testlib.h\"",
+		"files/testlib.h":   "",
+		"files/olymp.sty":   "",
+		"files/grader.cpp":  "",
+		"tests/01":          "1 2\n",
+		"tests/01.a":        "3\n",
+		"tests/02":          "2 2\n",
+		"tests/02.a":        "4\n",
+		"tests/03":          "3 3\n",
+		"tests/03.a":        "6\n",
+		"solutions/sol.cpp": "",
+	}
+	storage := &countingStorage{files: map[string][]byte{"a-plus-b.zip": zipArchive(t, files)}}
+	cache, err := NewCache(CacheConfig{Dir: t.TempDir()}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := cache.Get(context.Background(), "a-plus-b.zip", "3")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(pkg.Manifest.Tests) != 3 || pkg.Manifest.Checker == nil {
+		t.Fatalf("Unexpected manifest: %+v", pkg.Manifest)
+	}
+
+	// Standard packages without generated answers can't be judged
+	delete(files, "tests/03.a")
+	storage.files["no-answers.zip"] = zipArchive(t, files)
+	if _, err := cache.Get(context.Background(), "no-answers.zip", "3"); err == nil {
+		t.Fatal("Package with missing answers must fail")
+	}
+}
@@ -1,25 +1,24 @@
 package rabbitmq
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/cutekitek/rankode-runner/internal/mappers"
 	"github.com/cutekitek/rankode-runner/internal/repository/dto"
 	"github.com/cutekitek/rankode-runner/internal/repository/models"
-	"github.com/cutekitek/rankode-runner/internal/runner"
+	"github.com/cutekitek/rankode-runner/internal/worker"
 	"github.com/pkg/errors"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 const (
-	reqQueue  = "rankode-req"
-	respQueue = "rankode-resp"
+	reqQueue       = "rankode-req"
+	respQueue      = "rankode-resp"
+	cancelExchange = "rankode-cancel"
 )
 
 type RabbitMqHandlerConfig struct {
@@ -28,41 +27,64 @@ type RabbitMqHandlerConfig struct {
 	Host         string
 	Port         int
 	WorkersCount int
-}
-
-type FileStorage interface {
-	GetFile(ctx context.Context, filename string) (io.Reader, error)
+	// Request queues, defaults to single rankode-req queue
+	Queues []QueueConfig
+	// If greater than zero request queues are declared with x-max-priority argument
+	MaxPriority int
+	// Queue for responses to requests without reply-to, defaults to rankode-resp
+	ResponseQueue string
+	// Topic exchange for attempt progress events with attempt id as routing key.
+	// Events are not published if empty
+	EventsExchange string
+	// Fanout exchange with attempt cancel requests, defaults to rankode-cancel
+	CancelExchange string
 }
 
 type RabbitMQHandler struct {
 	cfg          RabbitMqHandlerConfig
-	runner       runner.Runner
 	conn         *amqp.Connection
 	consumerChan *amqp.Channel
 	producerChan *amqp.Channel
-	tasksChan    chan models.AttemptRequest
-	wg           *sync.WaitGroup
-	closed       bool
-	fileStorage  FileStorage
+	scheduler    *scheduler
+	// ids of attempts to cancel
+	cancelled chan int64
+	closed    bool
 }
 
-func NewRabbitMQHandler(cfg RabbitMqHandlerConfig, runner runner.Runner, storage FileStorage) (*RabbitMQHandler, error) {
-	return &RabbitMQHandler{cfg: cfg, runner: runner, wg: &sync.WaitGroup{}, tasksChan: make(chan models.AttemptRequest), fileStorage: storage}, nil
+// NewRabbitMQHandler creates handler which is both job source and result sink for the worker pool
+func NewRabbitMQHandler(cfg RabbitMqHandlerConfig) (*RabbitMQHandler, error) {
+	if len(cfg.Queues) == 0 {
+		cfg.Queues = []QueueConfig{{Name: reqQueue, Weight: 1}}
+	}
+	if cfg.ResponseQueue == "" {
+		cfg.ResponseQueue = respQueue
+	}
+	if cfg.CancelExchange == "" {
+		cfg.CancelExchange = cancelExchange
+	}
+	names := make(map[string]bool, len(cfg.Queues))
+	for _, q := range cfg.Queues {
+		if names[q.Name] {
+			return nil, fmt.Errorf("queue %s is configured twice", q.Name)
+		}
+		names[q.Name] = true
+	}
+	return &RabbitMQHandler{
+		cfg:       cfg,
+		scheduler: newScheduler(cfg.Queues),
+		cancelled: make(chan int64, 64),
+	}, nil
 }
 
 func (r *RabbitMQHandler) Start() error {
 	if err := r.connect(); err != nil {
 		return err
 	}
-	if err := r.startConsumer(); err != nil {
-		return errors.Wrap(err, "failed to start consumer")
-	}
 	if err := r.startProducer(); err != nil {
-		return errors.Wrap(err, "failed to start consumer")
+		return errors.Wrap(err, "failed to start producer")
 	}
-	for i := 0; i < r.cfg.WorkersCount; i++ {
-		r.wg.Add(1)
-		go r.worker()
+	if err := r.startConsumer(); err != nil {
+		return errors.Wrap(err, "failed to start consumer")
 	}
 	return nil
 }
@@ -72,17 +94,56 @@ func (r *RabbitMQHandler) startConsumer() error {
 	if err != nil {
 		return err
 	}
-	queue, err := channel.QueueDeclare(reqQueue, false, false, false, false, nil)
+	var args amqp.Table
+	if r.cfg.MaxPriority > 0 {
+		args = amqp.Table{"x-max-priority": int32(r.cfg.MaxPriority)}
+	}
+	r.scheduler.reset()
+	// Don't take more attempts from the broker than we are able to run, otherwise
+	// the rest stays in the queues for other runners. Workers are split between
+	// the queues by weight, so a low weight queue can't fill all of them.
+	prefetch := prefetchCounts(r.cfg.Queues, r.cfg.WorkersCount)
+	for i, q := range r.cfg.Queues {
+		queue, err := channel.QueueDeclare(q.Name, false, false, false, false, args)
+		if err != nil {
+			return err
+		}
+		// Applies to the consumer started next
+		if err := channel.Qos(prefetch[i], 0, false); err != nil {
+			return err
+		}
+		del, err := channel.Consume(queue.Name, "", false, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+		go r.listener(q.Name, del)
+	}
+	if err := r.startCancelConsumer(channel); err != nil {
+		return errors.Wrap(err, "failed to start cancel consumer")
+	}
+
+	r.consumerChan = channel
+	return nil
+}
+
+// startCancelConsumer binds exclusive queue to the cancel exchange. Cancel requests are
+// broadcast to every runner because any of them may be processing the attempt.
+func (r *RabbitMQHandler) startCancelConsumer(channel *amqp.Channel) error {
+	if err := channel.ExchangeDeclare(r.cfg.CancelExchange, amqp.ExchangeFanout, false, false, false, false, nil); err != nil {
+		return err
+	}
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
 	if err != nil {
 		return err
 	}
-	del, err := channel.Consume(queue.Name, "", true, false, false, false, nil)
+	if err := channel.QueueBind(queue.Name, "", r.cfg.CancelExchange, false, nil); err != nil {
+		return err
+	}
+	del, err := channel.Consume(queue.Name, "", true, true, false, false, nil)
 	if err != nil {
 		return err
 	}
-
-	r.consumerChan = channel
-	go r.listener(del)
+	go r.cancelListener(del)
 	return nil
 }
 
@@ -91,11 +152,16 @@ func (r *RabbitMQHandler) startProducer() error {
 	if err != nil {
 		return err
 	}
-	_, err = channel.QueueDeclare(respQueue, false, false, false, false, nil)
+	_, err = channel.QueueDeclare(r.cfg.ResponseQueue, false, false, false, false, nil)
 
 	if err != nil {
 		return err
 	}
+	if r.cfg.EventsExchange != "" {
+		if err := channel.ExchangeDeclare(r.cfg.EventsExchange, amqp.ExchangeTopic, false, false, false, false, nil); err != nil {
+			return err
+		}
+	}
 	r.producerChan = channel
 	return nil
 }
@@ -126,100 +192,108 @@ func (r *RabbitMQHandler) connect() error {
 	return nil
 }
 
-func (r *RabbitMQHandler) listener(taskChan <-chan amqp.Delivery) {
+func (r *RabbitMQHandler) listener(queue string, taskChan <-chan amqp.Delivery) {
 	for data := range taskChan {
 		var task models.AttemptRequest
 		if err := json.Unmarshal(data.Body, &task); err != nil {
 			slog.Error("invalid task message", "message", string(data.Body), "error", err)
+			data.Nack(false, false)
 			continue
 		}
-		r.tasksChan <- task
+		if task.Priority == 0 {
+			task.Priority = data.Priority
+		}
+		r.scheduler.push(queue, task, data)
+		r.sendEvent(data, mappers.RunEventToAttemptEvent(&task, dto.RunEvent{Type: models.AttemptEventQueued}))
 	}
 }
 
+func (r *RabbitMQHandler) cancelListener(cancelChan <-chan amqp.Delivery) {
+	for data := range cancelChan {
+		var req models.CancelRequest
+		if err := json.Unmarshal(data.Body, &req); err != nil {
+			slog.Error("invalid cancel message", "message", string(data.Body), "error", err)
+			continue
+		}
+		select {
+		case r.cancelled <- req.Id:
+		default:
+			slog.Warn("cancel request dropped, workers are not listening", "id", req.Id)
+		}
+	}
+}
+
+// Stop stops delivering attempts to workers. Unacknowledged attempts return to
+// the queue when the connection is closed.
+func (r *RabbitMQHandler) Stop() {
+	r.scheduler.close()
+}
+
+// Close closes the connection, must be called after workers finished
 func (r *RabbitMQHandler) Close() {
-	close(r.tasksChan)
-	r.wg.Wait()
+	r.closed = true
 	r.conn.Close()
 }
 
-func (r *RabbitMQHandler) worker() {
-	defer r.wg.Done()
-	for task := range r.tasksChan {
+func (r *RabbitMQHandler) Next() (*worker.Job, bool) {
+	j, ok := r.scheduler.next()
+	if !ok {
+		return nil, false
+	}
+	return &worker.Job{Request: &j.task, Data: j}, true
+}
 
-		request := &dto.RunRequest{Image: task.Language, Code: task.Code, Timeout: time.Duration(task.Timeout) * time.Millisecond, MaxOutputSize: int(task.MaxOutputSize)}
+func (r *RabbitMQHandler) Ack(wj *worker.Job) {
+	j := wj.Data.(*job)
+	if err := j.delivery.Ack(false); err != nil {
+		slog.Error("failed to ack task", "id", j.task.Id, "error", err)
+	}
+	r.scheduler.done(j)
+}
 
-		if task.VerificationFileName != "" {
-			input, err := r.fileStorage.GetFile(context.Background(), task.VerificationFileName)
-			if err != nil {
-				slog.Error("failed to load verification file", "error", err)
-				r.send(&models.AttemptResponse{
-					Id:     task.Id,
-					Status: models.AttemptStatusInternalError,
-					Error:  fmt.Sprintf("failed to load verification file %s: %s", task.VerificationFileName, err),
-				})
-				return
-			}
-			data, err := io.ReadAll(input)
-			if err != nil {
-				slog.Error("failed to load verification file", "error", err)
-				r.send(&models.AttemptResponse{
-					Id:     task.Id,
-					Status: models.AttemptStatusInternalError,
-					Error:  fmt.Sprintf("failed to load verification file %s: %s", task.VerificationFileName, err),
-				})
-				return
-			}
-			request.VerificationCode = string(data)
-		}
+func (r *RabbitMQHandler) Cancelled() <-chan int64 {
+	return r.cancelled
+}
 
-		for _, test := range task.TestCases {
-			input, err := r.fileStorage.GetFile(context.Background(), test.InputFileName)
-			if err != nil {
-				slog.Error("failed to load test file", "error", err)
-				r.send(&models.AttemptResponse{
-					Id:     task.Id,
-					Status: models.AttemptStatusInternalError,
-					Error:  fmt.Sprintf("failed to load test file %s: %s", test.InputFileName, err),
-				})
-				return
-			}
-			data, err := io.ReadAll(input)
-			if err != nil {
-				slog.Error("failed to load test file", "error", err)
-				r.send(&models.AttemptResponse{
-					Id:     task.Id,
-					Status: models.AttemptStatusInternalError,
-					Error:  fmt.Sprintf("failed to load test file %s: %s", test.InputFileName, err),
-				})
-				return
-			}
+func (r *RabbitMQHandler) SendResult(wj *worker.Job, resp *models.AttemptResponse) {
+	r.send(wj.Data.(*job).delivery, resp)
+}
 
-			request.Input = append(request.Input, string(data))
-		}
-		result, err := r.runner.Run(request)
-		if err != nil {
-			slog.Error("failed to run task", "error", err)
-			r.send(&models.AttemptResponse{
-				Id:     task.Id,
-				Status: models.AttemptStatusInternalError,
-			})
-			continue
-		}
-		r.send(mappers.RunResultToAttemptResult(&task, result))
-	}
-	slog.Info("end worker")
+func (r *RabbitMQHandler) SendEvent(wj *worker.Job, event *models.AttemptEvent) {
+	r.sendEvent(wj.Data.(*job).delivery, event)
 }
 
-func (r *RabbitMQHandler) send(data *models.AttemptResponse) {
+// send publishes the response to the reply-to queue of the request, or to the
+// configured response queue if the request has none.
+func (r *RabbitMQHandler) send(req amqp.Delivery, data *models.AttemptResponse) {
 	if !r.closed {
+		queue := req.ReplyTo
+		if queue == "" {
+			queue = r.cfg.ResponseQueue
+		}
 		body, _ := json.Marshal(data)
-		err := r.producerChan.Publish("", respQueue, false, false, amqp.Publishing{
-			ContentType: "text/plain",
-			Body:        []byte(body),
+		err := r.producerChan.Publish("", queue, false, false, amqp.Publishing{
+			ContentType:   "text/plain",
+			CorrelationId: req.CorrelationId,
+			Body:          []byte(body),
 		})
 		if err != nil {
 			slog.Error("failed to send response to queue", "error", err)
 		}
 	}
 }
+
+func (r *RabbitMQHandler) sendEvent(req amqp.Delivery, event *models.AttemptEvent) {
+	if r.closed || r.cfg.EventsExchange == "" {
+		return
+	}
+	body, _ := json.Marshal(event)
+	err := r.producerChan.Publish(r.cfg.EventsExchange, strconv.FormatInt(event.Id, 10), false, false, amqp.Publishing{
+		ContentType:   "application/json",
+		CorrelationId: req.CorrelationId,
+		Body:          body,
+	})
+	if err != nil {
+		slog.Warn("failed to send attempt event", "id", event.Id, "error", err)
+	}
+}
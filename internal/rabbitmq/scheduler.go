@@ -0,0 +1,178 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type QueueConfig struct {
+	Name string
+	// Share of workers the queue gets while other queues also have pending attempts
+	Weight int
+	// Max attempts from the queue processed at once, 0 means no limit
+	Workers int
+}
+
+// ParseQueueConfig parses queue definition in format name[:weight[:workers]]
+func ParseQueueConfig(s string) (QueueConfig, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	cfg := QueueConfig{Name: parts[0], Weight: 1}
+	if cfg.Name == "" || len(parts) > 3 {
+		return cfg, fmt.Errorf("invalid queue definition %q", s)
+	}
+	if len(parts) > 1 {
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight <= 0 {
+			return cfg, fmt.Errorf("invalid weight in queue definition %q", s)
+		}
+		cfg.Weight = weight
+	}
+	if len(parts) > 2 {
+		workers, err := strconv.Atoi(parts[2])
+		if err != nil || workers < 0 {
+			return cfg, fmt.Errorf("invalid workers quota in queue definition %q", s)
+		}
+		cfg.Workers = workers
+	}
+	return cfg, nil
+}
+
+// prefetchCounts splits workers between queues by weight. Every queue gets at least
+// one attempt and at most its workers quota.
+func prefetchCounts(queues []QueueConfig, workers int) []int {
+	total := 0
+	for _, q := range queues {
+		total += q.Weight
+	}
+	res := make([]int, len(queues))
+	for i, q := range queues {
+		res[i] = max(workers*q.Weight/total, 1)
+		if q.Workers > 0 {
+			res[i] = min(res[i], q.Workers)
+		}
+	}
+	return res
+}
+
+type job struct {
+	task     models.AttemptRequest
+	delivery amqp.Delivery
+	queue    *queueState
+}
+
+type queueState struct {
+	cfg     QueueConfig
+	pending []*job
+	running int
+	// smooth weighted round robin counter
+	current int
+}
+
+func (q *queueState) available() bool {
+	return len(q.pending) > 0 && (q.cfg.Workers == 0 || q.running < q.cfg.Workers)
+}
+
+// scheduler distributes attempts from several queues between workers using
+// smooth weighted round robin, respecting per-queue worker quotas. Inside a
+// queue attempts with higher priority are taken first.
+type scheduler struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues map[string]*queueState
+	order  []*queueState
+	closed bool
+}
+
+func newScheduler(queues []QueueConfig) *scheduler {
+	s := &scheduler{queues: make(map[string]*queueState, len(queues))}
+	s.cond = sync.NewCond(&s.mu)
+	for _, cfg := range queues {
+		q := &queueState{cfg: cfg}
+		s.queues[cfg.Name] = q
+		s.order = append(s.order, q)
+	}
+	return s
+}
+
+func (s *scheduler) push(queue string, task models.AttemptRequest, delivery amqp.Delivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.queues[queue]
+	j := &job{task: task, delivery: delivery, queue: q}
+	i := len(q.pending)
+	for i > 0 && q.pending[i-1].task.Priority < task.Priority {
+		i--
+	}
+	q.pending = append(q.pending, nil)
+	copy(q.pending[i+1:], q.pending[i:])
+	q.pending[i] = j
+	s.cond.Signal()
+}
+
+// next blocks until there is an attempt to process. Returns false after close
+func (s *scheduler) next() (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if s.closed {
+			return nil, false
+		}
+		if q := s.pick(); q != nil {
+			j := q.pending[0]
+			q.pending[0] = nil
+			q.pending = q.pending[1:]
+			q.running++
+			return j, true
+		}
+		s.cond.Wait()
+	}
+}
+
+func (s *scheduler) pick() *queueState {
+	var best *queueState
+	total := 0
+	for _, q := range s.order {
+		if !q.available() {
+			continue
+		}
+		q.current += q.cfg.Weight
+		total += q.cfg.Weight
+		if best == nil || q.current > best.current {
+			best = q
+		}
+	}
+	if best != nil {
+		best.current -= total
+	}
+	return best
+}
+
+func (s *scheduler) done(j *job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.queue.running--
+	s.cond.Broadcast()
+}
+
+// reset drops pending attempts. Used on reconnect because deliveries from a closed
+// channel can't be acknowledged and will be redelivered by the broker.
+func (s *scheduler) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.order {
+		q.pending = nil
+		q.current = 0
+	}
+}
+
+func (s *scheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
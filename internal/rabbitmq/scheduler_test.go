@@ -0,0 +1,19 @@
+package rabbitmq
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPrefetchCounts(t *testing.T) {
+	queues := []QueueConfig{{Name: "contest", Weight: 4}, {Name: "practice", Weight: 1}, {Name: "rejudge", Weight: 1, Workers: 2}}
+	if counts := prefetchCounts(queues, 12); !slices.Equal(counts, []int{8, 2, 2}) {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+	if counts := prefetchCounts(queues, 3); !slices.Equal(counts, []int{2, 1, 1}) {
+		t.Errorf("Every queue must get an attempt: %v", counts)
+	}
+	if counts := prefetchCounts(queues[:1], 5); !slices.Equal(counts, []int{5}) {
+		t.Errorf("Single queue must get all workers: %v", counts)
+	}
+}
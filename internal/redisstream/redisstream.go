@@ -0,0 +1,307 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/worker"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// Field of stream entries holding the JSON message
+const dataField = "data"
+
+// How long a blocking read waits, bounds the time Stop takes
+const readBlock = time.Second
+
+type Config struct {
+	Addr     string
+	Password string
+	DB       int
+	// Stream with attempt requests
+	Stream string
+	// Consumer group shared by all runners, created if not exists
+	Group string
+	// Consumer name of this runner, defaults to hostname
+	Consumer string
+	// Stream for attempt responses
+	ResponseStream string
+	// Approximate max length of the response stream, zero means no limit
+	ResponseMaxLen int64
+	// Prefix of pub/sub channels for progress events, the attempt id is appended.
+	// Events are not published if empty
+	EventsChannel string
+	// Pub/sub channel with cancel requests, disabled if empty
+	CancelChannel string
+	// Attempt pending longer than this is claimed from a dead runner. While the
+	// attempt is running the runner keeps it fresh
+	ClaimIdle time.Duration
+	// Attempt is dropped after so many deliveries, e.g. if it crashes runners.
+	// Zero means no limit
+	MaxDeliver int64
+}
+
+type message struct {
+	id   string
+	stop chan struct{}
+}
+
+// Source reads attempts from a Redis stream with a consumer group. It is both
+// job source and result sink for the worker pool.
+type Source struct {
+	cfg       Config
+	client    *redis.Client
+	cancelSub *redis.PubSub
+	cancelled chan int64
+	ctx       context.Context
+	stop      context.CancelFunc
+
+	// guards claim state, Next is called by all workers
+	mu        sync.Mutex
+	claimNext string
+	claimedAt time.Time
+}
+
+func NewSource(cfg Config) *Source {
+	if cfg.Consumer == "" {
+		cfg.Consumer, _ = os.Hostname()
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = time.Minute
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &Source{cfg: cfg, cancelled: make(chan int64, 64), ctx: ctx, stop: stop, claimNext: "0-0"}
+}
+
+func (s *Source) Start() error {
+	s.client = redis.NewClient(&redis.Options{Addr: s.cfg.Addr, Password: s.cfg.Password, DB: s.cfg.DB})
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+	err := s.client.XGroupCreateMkStream(ctx, s.cfg.Stream, s.cfg.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return errors.Wrap(err, "failed to create consumer group")
+	}
+
+	if s.cfg.CancelChannel != "" {
+		s.cancelSub = s.client.Subscribe(s.ctx, s.cfg.CancelChannel)
+		if _, err := s.cancelSub.Receive(ctx); err != nil {
+			return errors.Wrap(err, "failed to subscribe to cancel channel")
+		}
+		go s.cancelListener(s.cancelSub.Channel())
+	}
+	return nil
+}
+
+func (s *Source) cancelListener(msgs <-chan *redis.Message) {
+	for msg := range msgs {
+		var req models.CancelRequest
+		if err := json.Unmarshal([]byte(msg.Payload), &req); err != nil {
+			slog.Error("invalid cancel message", "message", msg.Payload, "error", err)
+			continue
+		}
+		select {
+		case s.cancelled <- req.Id:
+		default:
+			slog.Warn("cancel request dropped, workers are not listening", "id", req.Id)
+		}
+	}
+}
+
+// Next reads one attempt when a worker is free, so attempts not taken yet stay
+// in the stream for other runners. Attempts of dead runners are claimed first.
+func (s *Source) Next() (*worker.Job, bool) {
+	for s.ctx.Err() == nil {
+		msg, err := s.claim()
+		if err == nil && msg == nil {
+			msg, err = s.read()
+		}
+		if err != nil {
+			if s.ctx.Err() == nil {
+				slog.Error("failed to read attempt", "error", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+		if msg == nil {
+			continue
+		}
+		if job := s.parse(msg); job != nil {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Source) read() (*redis.XMessage, error) {
+	streams, err := s.client.XReadGroup(s.ctx, &redis.XReadGroupArgs{
+		Group:    s.cfg.Group,
+		Consumer: s.cfg.Consumer,
+		Streams:  []string{s.cfg.Stream, ">"},
+		Count:    1,
+		Block:    readBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			return &msg, nil
+		}
+	}
+	return nil, nil
+}
+
+// claim takes over one attempt pending longer than ClaimIdle. The whole
+// pending list is scanned at most once per ClaimIdle/2.
+func (s *Source) claim() (*redis.XMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimNext == "0-0" && time.Since(s.claimedAt) < s.cfg.ClaimIdle/2 {
+		return nil, nil
+	}
+	for {
+		msgs, next, err := s.client.XAutoClaim(s.ctx, &redis.XAutoClaimArgs{
+			Stream:   s.cfg.Stream,
+			Group:    s.cfg.Group,
+			Consumer: s.cfg.Consumer,
+			MinIdle:  s.cfg.ClaimIdle,
+			Start:    s.claimNext,
+			Count:    1,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		s.claimNext = next
+		if next == "0-0" {
+			s.claimedAt = time.Now()
+		}
+		if len(msgs) == 0 {
+			return nil, nil
+		}
+		msg := msgs[0]
+		if s.cfg.MaxDeliver > 0 && s.deliveries(msg.ID) > s.cfg.MaxDeliver {
+			slog.Error("attempt dropped after too many deliveries", "message", msg.Values[dataField])
+			s.ack(msg.ID)
+			if next == "0-0" {
+				return nil, nil
+			}
+			continue
+		}
+		slog.Warn("claimed attempt of a dead runner", "message_id", msg.ID)
+		return &msg, nil
+	}
+}
+
+func (s *Source) deliveries(id string) int64 {
+	pending, err := s.client.XPendingExt(s.ctx, &redis.XPendingExtArgs{
+		Stream: s.cfg.Stream,
+		Group:  s.cfg.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 0
+	}
+	return pending[0].RetryCount
+}
+
+func (s *Source) parse(msg *redis.XMessage) *worker.Job {
+	var task models.AttemptRequest
+	data, _ := msg.Values[dataField].(string)
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		slog.Error("invalid task message", "message", data, "error", err)
+		s.ack(msg.ID)
+		return nil
+	}
+	m := &message{id: msg.ID, stop: make(chan struct{})}
+	go s.keepFresh(m)
+	return &worker.Job{Request: &task, Data: m}
+}
+
+// keepFresh resets idle time of the attempt while it is judged, so long
+// attempts are not claimed by other runners.
+func (s *Source) keepFresh(m *message) {
+	ticker := time.NewTicker(s.cfg.ClaimIdle / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := s.client.XClaimJustID(context.Background(), &redis.XClaimArgs{
+				Stream:   s.cfg.Stream,
+				Group:    s.cfg.Group,
+				Consumer: s.cfg.Consumer,
+				Messages: []string{m.id},
+			}).Err()
+			if err != nil {
+				slog.Warn("failed to refresh attempt", "message_id", m.id, "error", err)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (s *Source) ack(id string) {
+	if err := s.client.XAck(context.Background(), s.cfg.Stream, s.cfg.Group, id).Err(); err != nil {
+		slog.Error("failed to ack task", "message_id", id, "error", err)
+	}
+}
+
+func (s *Source) Ack(job *worker.Job) {
+	m := job.Data.(*message)
+	close(m.stop)
+	s.ack(m.id)
+}
+
+func (s *Source) Stop() {
+	s.stop()
+}
+
+func (s *Source) Close() {
+	if s.cancelSub != nil {
+		s.cancelSub.Close()
+	}
+	if s.client != nil {
+		s.client.Close()
+	}
+}
+
+func (s *Source) Cancelled() <-chan int64 {
+	return s.cancelled
+}
+
+func (s *Source) SendResult(job *worker.Job, resp *models.AttemptResponse) {
+	body, _ := json.Marshal(resp)
+	err := s.client.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: s.cfg.ResponseStream,
+		MaxLen: s.cfg.ResponseMaxLen,
+		Approx: s.cfg.ResponseMaxLen > 0,
+		Values: map[string]any{dataField: body},
+	}).Err()
+	if err != nil {
+		slog.Error("failed to send response", "error", err)
+	}
+}
+
+func (s *Source) SendEvent(job *worker.Job, event *models.AttemptEvent) {
+	if s.cfg.EventsChannel == "" {
+		return
+	}
+	body, _ := json.Marshal(event)
+	if err := s.client.Publish(context.Background(), s.cfg.EventsChannel+":"+strconv.FormatInt(event.Id, 10), body).Err(); err != nil {
+		slog.Warn("failed to send attempt event", "id", event.Id, "error", err)
+	}
+}
@@ -0,0 +1,110 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+	"github.com/cutekitek/rankode-runner/internal/worker"
+	"github.com/redis/go-redis/v9"
+)
+
+func testConfig(addr string) Config {
+	return Config{
+		Addr:           addr,
+		Stream:         "rankode-req",
+		Group:          "rankode-runner",
+		Consumer:       "runner-1",
+		ResponseStream: "rankode-resp",
+		ClaimIdle:      200 * time.Millisecond,
+		MaxDeliver:     3,
+	}
+}
+
+func startPool(t *testing.T, cfg Config) {
+	t.Helper()
+	source := NewSource(cfg)
+	if err := source.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	pool := worker.NewPool(2, judge.NewJudge(judge.Config{}, &testutil.EchoRunner{}, testutil.Storage{"in1": "1"}, nil), source, source)
+	pool.Start()
+	t.Cleanup(func() {
+		pool.Close()
+		source.Close()
+	})
+}
+
+func addAttempt(t *testing.T, client *redis.Client, stream string, id int64) {
+	t.Helper()
+	body, _ := json.Marshal(models.AttemptRequest{Id: id, TestCases: []models.TestCase{{Id: 1, InputFileName: "in1"}}})
+	if err := client.XAdd(context.Background(), &redis.XAddArgs{Stream: stream, Values: map[string]any{dataField: body}}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readResponse(t *testing.T, client *redis.Client, stream string) models.AttemptResponse {
+	t.Helper()
+	var resp models.AttemptResponse
+	streams, err := client.XRead(context.Background(), &redis.XReadArgs{Streams: []string{stream, "0"}, Count: 1, Block: 5 * time.Second}).Result()
+	if err != nil {
+		t.Fatalf("no response received: %v", err)
+	}
+	msg := streams[0].Messages[0]
+	if err := json.Unmarshal([]byte(msg.Values[dataField].(string)), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestSource_AcknowledgesAttempts(t *testing.T) {
+	srv := miniredis.RunT(t)
+	cfg := testConfig(srv.Addr())
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+	startPool(t, cfg)
+
+	addAttempt(t, client, cfg.Stream, 7)
+	resp := readResponse(t, client, cfg.ResponseStream)
+	if resp.Id != 7 || resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	pending, err := client.XPending(context.Background(), cfg.Stream, cfg.Group).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Count != 0 {
+		t.Fatalf("Attempt is not acknowledged, pending %d", pending.Count)
+	}
+}
+
+func TestSource_ClaimsAttemptsOfDeadRunner(t *testing.T) {
+	srv := miniredis.RunT(t)
+	cfg := testConfig(srv.Addr())
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.XGroupCreateMkStream(ctx, cfg.Stream, cfg.Group, "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A runner which took the attempt and died
+	addAttempt(t, client, cfg.Stream, 8)
+	if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{Group: cfg.Group, Consumer: "dead", Streams: []string{cfg.Stream, ">"}, Count: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(cfg.ClaimIdle)
+
+	startPool(t, cfg)
+	resp := readResponse(t, client, cfg.ResponseStream)
+	if resp.Id != 8 || resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+}
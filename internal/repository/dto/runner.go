@@ -7,19 +7,51 @@ import (
 )
 
 type RunRequest struct {
-	Image            string
-	Code             string
-	Input            []string
+	Image string
+	// Written to the code file of the language, may be empty if Files has it
+	Code string
+	// Additional source files, paths are relative to the working directory
+	Files []File
+	// Problem files placed read-only next to the sources before build, the
+	// submission can't replace them unless they are in a subdirectory
+	Attachments []File
+	Input       []string
+	// Files in the working directory the solution reads tests from and writes
+	// outputs to, stdin and stdout are used if empty
+	InputFile        string
+	OutputFile       string
 	Timeout          time.Duration
 	MemoryLimit      int
 	MaxFilesSize     int
 	MaxOutputSize    int
 	VerificationCode string
+	// Answers for the checker, one for each input
+	Expected []string
+	// Optional checker of test outputs
+	Checker *Checker
+	// Optional hook called on attempt progress, must not block for long
+	OnEvent func(RunEvent)
+}
+
+type File struct {
+	Path    string
+	Content string
+}
+
+// Checker is built in its language and run after the tests as
+// `checker input output answer` like testlib checkers
+type Checker struct {
+	Image string
+	Code  string
+	// Additional files by name placed next to the code, e.g. testlib.h
+	Files map[string]string
 }
 
 type RunResult struct {
-	Status        models.AttemptStatus
-	Error         string
+	Status models.AttemptStatus
+	Error  string
+	// Diagnostics of the compiler, also set when the build succeeds
+	CompileOutput string
 	Output        []RunCaseResult
 	ExecutionTime time.Duration
 	MemoryUsage   int
@@ -29,4 +61,15 @@ type RunCaseResult struct {
 	Output        string
 	Status        models.TestCaseStatus
 	ExecutionTime int64
+	// Verdict of the checker, nil if the test wasn't checked
+	Matched        *bool
+	CheckerComment string
+}
+
+type RunEvent struct {
+	Type models.AttemptEventType
+	// Index of the test in RunRequest.Input
+	Test          int
+	Status        models.TestCaseStatus
+	ExecutionTime int64
 }
@@ -19,32 +19,94 @@ const (
 	AttemptStatusRunFailed     AttemptStatus = iota
 	AttemptStatusInternalError AttemptStatus = iota
 	AttemptStatusCreated       AttemptStatus = iota
+	AttemptStatusCancelled     AttemptStatus = iota
+)
+
+type AttemptEventType uint8
+
+const (
+	AttemptEventQueued       AttemptEventType = iota
+	AttemptEventCompiling    AttemptEventType = iota
+	AttemptEventCompiled     AttemptEventType = iota
+	AttemptEventTestStarted  AttemptEventType = iota
+	AttemptEventTestFinished AttemptEventType = iota
 )
 
 type AttemptRequest struct {
-	Id            int64  `json:"id"`
-	Language      string `json:"language"`
-	Code          string `json:"code"`
-	MemoryLimit   int64  `json:"memory_limit"`
-	Timeout       int64  `json:"timeout"`
-	MaxOutputSize int64  `json:"max_output_size"`
+	Id       int64  `json:"id"`
+	Language string `json:"language"`
+	Code     string `json:"code"`
+	// Bytes per test run, the runner default if zero
+	MemoryLimit   int64 `json:"memory_limit"`
+	Timeout       int64 `json:"timeout"`
+	MaxOutputSize int64 `json:"max_output_size"`
+	// Attempts with higher priority are processed first within the same queue
+	Priority uint8 `json:"priority"`
+
+	// Additional source files of multi-file submissions
+	Files []SourceFile `json:"files,omitempty"`
+	// Zip archive with source files, base64 in JSON
+	Archive []byte `json:"archive,omitempty"`
+	// Problem files from storage placed read-only next to the sources, e.g. grader.h
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Files the solution reads tests from and writes outputs to instead of stdin
+	// and stdout, e.g. input.txt
+	InputFile  string `json:"input_file,omitempty"`
+	OutputFile string `json:"output_file,omitempty"`
 
 	TestCases            []TestCase `json:"test_cases"`
 	VerificationFileName string     `json:"verification_file"`
+	// Problem package in file storage with tests, checker and limits. Test cases
+	// without input are taken from the package by id, all tests if there are none
+	Package string `json:"package,omitempty"`
+	// Version of the package content, a changed package must get a new version
+	PackageVersion string `json:"package_version,omitempty"`
+}
+
+type SourceFile struct {
+	// Slash separated path relative to the working directory
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+type Attachment struct {
+	// Slash separated path relative to the working directory
+	Path string `json:"path"`
+	// Name of the file in file storage
+	FileName string `json:"file"`
 }
 
 type TestCase struct {
 	Id            int64  `json:"id"`
 	Order         int32  `json:"order"`
 	InputFileName string `json:"input_file"`
+	// Inline input used instead of InputFileName if set
+	Input *string `json:"input,omitempty"`
+	// Optional expected output, the output is compared with it ignoring trailing whitespace
+	Expected *string `json:"expected,omitempty"`
+	// Test group from the problem package
+	Group string `json:"group,omitempty"`
 }
 
 type AttemptResponse struct {
-	Id          int64         `json:"id"`
-	Status      AttemptStatus `json:"status"`
-	Error       string        `json:"error"`
-	MemoryUsage int64         `json:"memory_usage"`
-	Tests       []TestStatus  `json:"tests"`
+	Id     int64         `json:"id"`
+	Status AttemptStatus `json:"status"`
+	Error  string        `json:"error"`
+	// Warnings and errors of the compiler, truncated to 64 KiB
+	CompileOutput string       `json:"compile_output,omitempty"`
+	MemoryUsage   int64        `json:"memory_usage"`
+	Tests         []TestStatus `json:"tests"`
+	// Groups of the problem package with tests in the attempt
+	Groups []GroupResult `json:"groups,omitempty"`
+}
+
+type GroupResult struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+	// Points of the group if it and all groups it depends on are passed, zero otherwise
+	Score float64 `json:"score"`
+	// All tests of the group are complete and matched
+	Passed bool `json:"passed"`
 }
 
 type TestStatus struct {
@@ -52,4 +114,22 @@ type TestStatus struct {
 	Status        TestCaseStatus `json:"status"`
 	Output        string         `json:"output"`
 	ExecutionTime int64          `json:"execution_time"`
+	// Set only for tests with expected output or checker
+	Matched        *bool  `json:"matched,omitempty"`
+	CheckerComment string `json:"checker_comment,omitempty"`
+	Group          string `json:"group,omitempty"`
+}
+
+type AttemptEvent struct {
+	Id   int64            `json:"id"`
+	Type AttemptEventType `json:"type"`
+	// Test fields are meaningful only for test events
+	CaseId        int64          `json:"test_id"`
+	Test          int            `json:"test"`
+	Status        TestCaseStatus `json:"status"`
+	ExecutionTime int64          `json:"execution_time"`
+}
+
+type CancelRequest struct {
+	Id int64 `json:"id"`
 }
@@ -1,7 +1,12 @@
 package models
 
 type Language struct {
-	Id        int
-	Name      string
-	ImageName string
+	// Name of the language config directory, attempts may use it or an alias
+	ImageName string   `json:"image"`
+	Name      string   `json:"name"`
+	Version   string   `json:"version,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Extension string   `json:"extension,omitempty"`
+	// Picked for aliases shared with other languages
+	Default bool `json:"default,omitempty"`
 }
@@ -1,8 +1,24 @@
 package runner
 
-import "github.com/cutekitek/rankode-runner/internal/repository/dto"
+import (
+	"context"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidRequest is wrapped by errors caused by the request itself, e.g. bad file paths.
+// The error message is safe to show to the user
+var ErrInvalidRequest = errors.New("invalid request")
 
 type Runner interface {
-	// Syncronosly runs a test. If there are not enough resources(ram or cpu) to run a test wait for other tasks to finish
-	Run(*dto.RunRequest) (*dto.RunResult, error)
+	// Syncronosly runs a test. If there are not enough resources(ram or cpu) to run a test wait for other tasks to finish.
+	// If ctx is cancelled running process is killed and ctx error is returned
+	Run(context.Context, *dto.RunRequest) (*dto.RunResult, error)
+}
+
+// LanguageLister is implemented by runners which know their languages
+type LanguageLister interface {
+	Languages() []models.Language
 }
@@ -0,0 +1,147 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/criyle/go-sandbox/container"
+	"github.com/criyle/go-sandbox/runner"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	checkerTimeout     = 10 * time.Second
+	checkerMemoryLimit = 512 * 1024 * 1024
+	checkerOutputLimit = 64 * 1024
+)
+
+// Exit codes of testlib checkers
+const (
+	checkerWrongAnswer       = 1
+	checkerPresentationError = 2
+	checkerFail              = 3
+	checkerPartiallyCorrect  = 7
+)
+
+// runChecker builds the checker and checks outputs of completed tests. It runs in a
+// container of the checker language with the slot of the attempt.
+func (r *SandboxRunner) runChecker(ctx context.Context, req *dto.RunRequest, result *dto.RunResult) error {
+	lang, err := r.getLangConfig(req.Checker.Image)
+	if err != nil {
+		return errors.Wrap(err, "failed to get checker language config")
+	}
+	cenv, err := r.getContainer(lang)
+	if err != nil {
+		return err
+	}
+	defer r.putContainer(cenv)
+	if err := cenv.Reset(); err != nil {
+		return fmt.Errorf("failed to reset container: %w", err)
+	}
+	codeFile := "code"
+	if lang.CodeFile != "" {
+		codeFile = lang.CodeFile
+	}
+	files := map[string]string{codeFile: req.Checker.Code}
+	for name, content := range req.Checker.Files {
+		if name == codeFile || strings.ContainsRune(name, '/') {
+			return fmt.Errorf("invalid checker file name %s", name)
+		}
+		files[name] = content
+	}
+	if err := writeFiles(cenv, files, 0777); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	lang = lang.withFiles(names)
+	if len(lang.BuildCmd) > 0 {
+		if output, err := r.build(ctx, lang, cenv, lang.BuildCmd); err != nil {
+			var buildErr *buildFailedError
+			if errors.As(err, &buildErr) {
+				return fmt.Errorf("checker build failed: %w: %s", err, output)
+			}
+			return err
+		}
+	}
+
+	args := append(slices.Clone(lang.RunCmd), "input.txt", "output.txt", "answer.txt")
+	for i := range result.Output {
+		test := &result.Output[i]
+		if test.Status != models.TestCaseStatusComplete {
+			continue
+		}
+		expected := ""
+		if i < len(req.Expected) {
+			expected = req.Expected[i]
+		}
+		err := writeFiles(cenv, map[string]string{
+			"input.txt":  req.Input[i],
+			"output.txt": test.Output,
+			"answer.txt": expected,
+		}, 0777)
+		if err != nil {
+			return err
+		}
+
+		params := lang.runParams(cenv, args, true)
+		params.MaxFileSize = checkerOutputLimit
+		params.Timeout = checkerTimeout
+		params.MemoryLimit = checkerMemoryLimit
+		params.MaxOutputSize = checkerOutputLimit
+		res, err := r.ExecuteInSandbox(ctx, params)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute checker")
+		}
+
+		comment := strings.TrimSpace(string(res.Error))
+		matched := false
+		switch {
+		case res.Status == runner.StatusNormal:
+			matched = true
+		case res.Status == runner.StatusNonzeroExitStatus && (res.ExitStatus == checkerWrongAnswer ||
+			res.ExitStatus == checkerPresentationError || res.ExitStatus == checkerPartiallyCorrect):
+		case res.Status == runner.StatusNonzeroExitStatus && res.ExitStatus == checkerFail:
+			return fmt.Errorf("checker failed on test %d: %s", i+1, comment)
+		default:
+			return fmt.Errorf("checker crashed on test %d (%s, exit code %d): %s", i+1, res.Status, res.ExitStatus, comment)
+		}
+		test.Matched = &matched
+		test.CheckerComment = comment
+	}
+	return nil
+}
+
+// writeFiles creates or replaces files in /w of the container
+func writeFiles(env container.Environment, files map[string]string, perm os.FileMode) error {
+	names := make([]string, 0, len(files))
+	cmds := make([]container.OpenCmd, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+		cmds = append(cmds, container.OpenCmd{Path: "/w/" + name, Flag: os.O_WRONLY | os.O_CREATE | os.O_TRUNC, Perm: perm})
+	}
+	opened, err := env.Open(cmds)
+	if err != nil {
+		return fmt.Errorf("failed to open files in container: %w", err)
+	}
+	defer func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}()
+	for i, f := range opened {
+		if _, err := io.Copy(f, strings.NewReader(files[names[i]])); err != nil {
+			return fmt.Errorf("failed to write %s: %w", names[i], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/criyle/go-sandbox/container"
+	"github.com/criyle/go-sandbox/pkg/mount"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// Numbers container directories in /tmp, shared by runners of the process
+var containerCount atomic.Int32
+
+// mountConfig is a host directory mounted into containers of a language
+type mountConfig struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Caches only. Command run on the host once to fill the source directory before
+	// containers use it, e.g. to prebuild the standard library
+	Warmup    []string          `json:"warmup,omitempty"`
+	WarmupEnv map[string]string `json:"warmup_env,omitempty"`
+}
+
+// profile identifies the mounts of the language, languages with equal mounts share
+// containers. It is empty for languages using only the base mounts.
+func (c *languageConfig) profile() string {
+	if len(c.Mounts) == 0 && len(c.Caches) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal([][]mountConfig{c.Mounts, c.Caches})
+	return string(data)
+}
+
+// acquireSlot waits until the runner can start one more attempt
+func (r *SandboxRunner) acquireSlot(ctx context.Context) error {
+	select {
+	case r.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *SandboxRunner) releaseSlot() {
+	<-r.slots
+}
+
+// getContainer returns an idle container with mounts of the language or creates
+// one. The caller must hold a slot.
+func (r *SandboxRunner) getContainer(lang *languageConfig) (*sandboxContainerEnv, error) {
+	profile := lang.profile()
+	select {
+	case c := <-r.idlePool(profile):
+		return c, nil
+	default:
+	}
+	return r.newContainer(profile, lang.Mounts, lang.Caches)
+}
+
+// putContainer keeps the container for later attempts or destroys it if there are
+// enough idle containers with its mounts
+func (r *SandboxRunner) putContainer(c *sandboxContainerEnv) {
+	select {
+	case r.idlePool(c.profile) <- c:
+	default:
+		destroyContainer(c)
+	}
+}
+
+func (r *SandboxRunner) idlePool(profile string) chan *sandboxContainerEnv {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pool, ok := r.idle[profile]
+	if !ok {
+		pool = make(chan *sandboxContainerEnv, r.Config.ContainersPoolSize)
+		r.idle[profile] = pool
+	}
+	return pool
+}
+
+// destroyIdle destroys idle containers of all profiles
+func (r *SandboxRunner) destroyIdle() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pool := range r.idle {
+		for len(pool) > 0 {
+			destroyContainer(<-pool)
+		}
+	}
+}
+
+func destroyContainer(c *sandboxContainerEnv) {
+	c.Destroy()
+	os.Remove(c.WorkDir)
+}
+
+// prepareContainers starts containers with the base mounts, most languages use them
+func (r *SandboxRunner) prepareContainers() error {
+	for i := 0; i < r.Config.ContainersPoolSize; i++ {
+		c, err := r.newContainer("", nil, nil)
+		if err != nil {
+			return err
+		}
+		r.idlePool("") <- c
+	}
+	return nil
+}
+
+func (r *SandboxRunner) newContainer(profile string, mounts, caches []mountConfig) (*sandboxContainerEnv, error) {
+	workdir := fmt.Sprintf("/tmp/rankode-container-%d", containerCount.Add(1)-1)
+	if err := os.MkdirAll(filepath.Join(workdir, "root"), 0777); err != nil {
+		return nil, fmt.Errorf("failed to create container root: %w", err)
+	}
+	c, err := prepareContainer(workdir, mounts, caches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create container")
+	}
+	return &sandboxContainerEnv{Environment: c, WorkDir: workdir, profile: profile}, nil
+}
+
+// prepareContainer builds a container with the base mounts and mounts of a language.
+// Mounts are read-only binds. Caches are overlays over the host directory, writes go
+// to the upper directory of the container and are kept between its attempts.
+func prepareContainer(workdir string, mounts, caches []mountConfig) (container.Environment, error) {
+	root := filepath.Join(workdir, "root")
+	mb := mount.NewBuilder().
+		WithBind("/bin", "bin", true).
+		WithBind("/lib", "lib", true).
+		WithBind("/lib64", "lib64", true).
+		WithBind("/usr", "usr", true).
+		WithBind("/etc/ld.so.cache", "etc/ld.so.cache", true).
+		WithBind("/etc/alternatives", "etc/alternatives", true).
+		WithBind("/etc/ssl", "etc/ssl", true).                         // SSL Certs
+		WithBind("/etc/pki", "etc/pki", true).                         // CA Certs (RedHat/Fedora)
+		WithBind("/etc/crypto-policies", "etc/crypto-policies", true). // Crypto policies
+		WithBind("/etc/ca-certificates", "etc/ca-certificates", true)  // Ubuntu/Debian Certs
+	for _, m := range mounts {
+		mb.WithBind(m.Source, strings.TrimPrefix(m.Target, "/"), true)
+	}
+	mb.WithProc().
+		WithBind("/dev/null", "dev/null", false).
+		WithBind("/dev/urandom", "dev/urandom", false).
+		WithBind("/dev/random", "dev/random", false)
+
+	for i, c := range caches {
+		target := strings.TrimPrefix(c.Target, "/")
+		upper := filepath.Join(workdir, fmt.Sprintf("cache-%d-upper", i))
+		work := filepath.Join(workdir, fmt.Sprintf("cache-%d-work", i))
+		for _, dir := range []string{c.Source, upper, work, filepath.Join(root, target)} {
+			if err := os.MkdirAll(dir, 0777); err != nil {
+				return nil, fmt.Errorf("failed to create cache directory: %w", err)
+			}
+		}
+		// Sandboxed users must be able to write the cache
+		for _, dir := range []string{upper, work} {
+			if err := os.Chmod(dir, 0777); err != nil {
+				return nil, fmt.Errorf("failed to chmod cache directory: %w", err)
+			}
+		}
+		mb.WithMount(mount.Mount{
+			Source: "overlay",
+			Target: target,
+			FsType: "overlay",
+			Data:   fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s,userxattr,index=off", c.Source, upper, work),
+			Flags:  unix.MS_NOSUID | unix.MS_NODEV,
+		})
+	}
+
+	mb.WithTmpfs("tmp", "size=128m,nr_inodes=4k").
+		// Sticky, so sandboxed processes can't remove files written by the container init
+		WithTmpfs("w", "size=32m,nr_inodes=4k,mode=1777").
+		FilterNotExist()
+
+	cloneFlag := unix.CLONE_NEWIPC | unix.CLONE_NEWNET | unix.CLONE_NEWNS | unix.CLONE_NEWPID | unix.CLONE_NEWUSER | unix.CLONE_NEWUTS
+
+	b := container.Builder{
+		Root:          root,
+		WorkDir:       "/w",
+		Mounts:        mb.Mounts,
+		Stderr:        os.Stderr,
+		CredGenerator: newCredGen(),
+		CloneFlags:    uintptr(cloneFlag),
+		SymbolicLinks: []container.SymbolicLink{
+			{LinkPath: "/dev/fd", Target: "/proc/self/fd"},
+			{LinkPath: "/dev/stdin", Target: "/proc/self/fd/0"},
+			{LinkPath: "/dev/stdout", Target: "/proc/self/fd/1"},
+			{LinkPath: "/dev/stderr", Target: "/proc/self/fd/2"},
+		},
+	}
+	return b.Build()
+}
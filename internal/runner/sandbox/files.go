@@ -0,0 +1,202 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/criyle/go-sandbox/container"
+	"github.com/criyle/go-sandbox/runner"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	rr "github.com/cutekitek/rankode-runner/internal/runner"
+)
+
+// /w is a tmpfs with 4k inodes
+const maxSourceFiles = 256
+
+// sourceFiles collects files of the request by path relative to /w. Paths must stay
+// inside /w and must not replace the code or verifier file.
+func sourceFiles(req *dto.RunRequest, lang *languageConfig) (map[string]string, error) {
+	codeFile := "code"
+	if lang.CodeFile != "" {
+		codeFile = lang.CodeFile
+	}
+	if len(req.Files)+len(req.Attachments) > maxSourceFiles {
+		return nil, fmt.Errorf("%w: too many files, max %d", rr.ErrInvalidRequest, maxSourceFiles)
+	}
+
+	files := make(map[string]string, len(req.Files)+2)
+	for _, f := range req.Files {
+		name, err := cleanPath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("%w: file %s is sent twice", rr.ErrInvalidRequest, f.Path)
+		}
+		files[name] = f.Content
+	}
+	if _, ok := files[codeFile]; !ok || req.Code != "" {
+		if ok {
+			return nil, fmt.Errorf("%w: file %s conflicts with code", rr.ErrInvalidRequest, codeFile)
+		}
+		files[codeFile] = req.Code
+	}
+	if req.VerificationCode != "" && lang.VerifierFile != "" {
+		if _, ok := files[lang.VerifierFile]; ok {
+			return nil, fmt.Errorf("%w: file %s is reserved for the verifier", rr.ErrInvalidRequest, lang.VerifierFile)
+		}
+		files[lang.VerifierFile] = req.VerificationCode
+	}
+	return files, nil
+}
+
+// attachmentFiles collects problem attachments by path relative to /w, they must not
+// collide with source files
+func attachmentFiles(req *dto.RunRequest, sources map[string]string) (map[string]string, error) {
+	files := make(map[string]string, len(req.Attachments))
+	for _, f := range req.Attachments {
+		name, err := cleanPath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := sources[name]; ok {
+			return nil, fmt.Errorf("%w: file %s is provided by the problem", rr.ErrInvalidRequest, f.Path)
+		}
+		files[name] = f.Content
+	}
+	return files, nil
+}
+
+// checkIOFiles makes sure files of file I/O don't replace files of the attempt
+func checkIOFiles(req *dto.RunRequest, files ...map[string]string) error {
+	for _, name := range []string{req.InputFile, req.OutputFile} {
+		if name == "" {
+			continue
+		}
+		if strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+			return fmt.Errorf("%w: invalid I/O file name %q", rr.ErrInvalidRequest, name)
+		}
+		for _, m := range files {
+			if _, ok := m[name]; ok {
+				return fmt.Errorf("%w: I/O file %s conflicts with a source file", rr.ErrInvalidRequest, name)
+			}
+		}
+	}
+	if req.InputFile != "" && req.InputFile == req.OutputFile {
+		return fmt.Errorf("%w: input and output files must differ", rr.ErrInvalidRequest)
+	}
+	return nil
+}
+
+// readFile reads a file in /w of the container. More than limit bytes are not read
+// and errFileTooLarge is returned, zero limit means no limit.
+func readFile(env container.Environment, name string, limit int64) ([]byte, error) {
+	opened, err := env.Open([]container.OpenCmd{{Path: "/w/" + name, Flag: os.O_RDONLY}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer opened[0].Close()
+	var r io.Reader = opened[0]
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return data[:limit], errFileTooLarge
+	}
+	return data, nil
+}
+
+var errFileTooLarge = errors.New("file is too large")
+
+// cleanPath validates a file path of the request. Names are expanded into build
+// commands by globs, so a component starting with a dash would pass as an option.
+func cleanPath(p string) (string, error) {
+	name := path.Clean(p)
+	if p == "" || strings.ContainsRune(p, '\\') || !isLocal(name) || hasOptionComponent(name) {
+		return "", fmt.Errorf("%w: invalid file path %q", rr.ErrInvalidRequest, p)
+	}
+	return name, nil
+}
+
+func hasOptionComponent(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// isLocal reports whether the slash separated path is relative and doesn't leave its root
+func isLocal(name string) bool {
+	return name != "." && !path.IsAbs(name) && name != ".." && !strings.HasPrefix(name, "../")
+}
+
+// makeDirs creates parent directories of files in /w. Container API can only open
+// files, so directories are created by mkdir running in the container.
+func (r *SandboxRunner) makeDirs(ctx context.Context, env container.Environment, files ...map[string]string) error {
+	var dirs []string
+	for _, m := range files {
+		for name := range m {
+			if dir := path.Dir(name); dir != "." {
+				dirs = append(dirs, "/w/"+dir)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+	res, err := r.ExecuteInSandbox(ctx, RunParams{
+		ContainerEnv:  env,
+		Args:          append([]string{"/bin/mkdir", "-p", "-m", "0777"}, dirs...),
+		Timeout:       5 * time.Second,
+		MaxFileSize:   1,
+		MaxOutputSize: 64 * 1024,
+	})
+	if err != nil {
+		return err
+	}
+	if res.Status != runner.StatusNormal {
+		return fmt.Errorf("failed to create directories: %s", res.Error)
+	}
+	return nil
+}
+
+// expandArgs replaces arguments with glob patterns, e.g. *.java, by matching file
+// names sorted alphabetically. Patterns without matches are left as is.
+func expandArgs(args []string, names []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	res := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			res = append(res, arg)
+			continue
+		}
+		var matches []string
+		for _, name := range names {
+			if ok, _ := path.Match(arg, name); ok {
+				matches = append(matches, name)
+			}
+		}
+		if len(matches) == 0 {
+			res = append(res, arg)
+			continue
+		}
+		slices.Sort(matches)
+		res = append(res, matches...)
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package sandbox
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	rr "github.com/cutekitek/rankode-runner/internal/runner"
+)
+
+func TestSourceFiles_Paths(t *testing.T) {
+	lang := &languageConfig{CodeFile: "main.c"}
+	for _, p := range []string{"", "/etc/passwd", "../x.c", "a\\b.c", "-fplugin=x.so", "-o", "src/-x.c", "./-x.c"} {
+		_, err := sourceFiles(&dto.RunRequest{Code: "int main;", Files: []dto.File{{Path: p}}}, lang)
+		if !errors.Is(err, rr.ErrInvalidRequest) {
+			t.Errorf("Path %q must be rejected, got %v", p, err)
+		}
+	}
+	files, err := sourceFiles(&dto.RunRequest{Code: "int main;", Files: []dto.File{{Path: "./lib/a-b.c"}}}, lang)
+	if err != nil {
+		t.Fatalf("sourceFiles failed: %v", err)
+	}
+	if _, ok := files["lib/a-b.c"]; !ok {
+		t.Fatalf("Unexpected files: %v", files)
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+	args := expandArgs([]string{"/usr/bin/gcc", "-o", "run", "*.c", "*.h"}, []string{"b.c", "a.c", "lib/c.c"})
+	if !slices.Equal(args, []string{"/usr/bin/gcc", "-o", "run", "a.c", "b.c", "*.h"}) {
+		t.Fatalf("Unexpected args: %v", args)
+	}
+}
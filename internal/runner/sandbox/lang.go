@@ -1,13 +1,31 @@
 package sandbox
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/criyle/go-sandbox/container"
 )
 
 type languageConfig struct {
+	// Display name, e.g. "Python 3"
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Aliases []string `json:"aliases"`
+	// Extension of source files, e.g. ".py"
+	Extension string `json:"extension"`
+	// Default language for aliases shared with other languages
+	Default bool `json:"default"`
+
 	BuildCmd         []string      `json:"build"`
 	RunCmd           []string      `json:"run"`
 	BuildMemoryLimit int           `json:"build_memory_limit"`
@@ -17,8 +35,31 @@ type languageConfig struct {
 	VerifierFile     string        `json:"verifier_file"`
 	VerifierBuildCmd []string      `json:"verifier_build"`
 	VerifierRunCmd   []string      `json:"verifier_run"`
+
+	// Environment of all commands, PATH defaults to /usr/local/bin:/usr/bin:/bin
+	Env map[string]string `json:"env"`
+	// Added to Env for run and verifier_run
+	RunEnv map[string]string `json:"run_env"`
+	// Bytes, 128 MiB by default
+	StackLimit uint64 `json:"stack_limit"`
+	// 2048 by default
+	OpenFiles uint64 `json:"open_files"`
+	// Memory limit of tests is multiplied by it to cover runtime overhead, e.g. of JVM
+	MemoryMultiplier float64 `json:"memory_multiplier"`
+
+	// Read-only host directories added to the base mounts of containers
+	Mounts []mountConfig `json:"mounts"`
+	// Overlays over host directories, e.g. a build cache. Writes are kept in the
+	// container and don't reach the host
+	Caches []mountConfig `json:"caches"`
 }
 
+const (
+	defaultPath       = "/usr/local/bin:/usr/bin:/bin"
+	defaultStackLimit = 128 * 1024 * 1024
+	defaultOpenFiles  = 2048
+)
+
 func NewLangConfigFromFile(path string) (*languageConfig, error) {
 	file, err := os.Open(filepath.Join(path, "config.json"))
 	if err != nil {
@@ -32,3 +73,129 @@ func NewLangConfigFromFile(path string) (*languageConfig, error) {
 	cfg.BuildTimeout *= time.Millisecond
 	return cfg, nil
 }
+
+// validate checks the config once it's loaded, so mistakes show up at start instead
+// of failing attempts
+func (c *languageConfig) validate() error {
+	if len(c.RunCmd) == 0 {
+		return errors.New("run command is empty")
+	}
+	for _, alias := range c.Aliases {
+		if alias == "" || strings.ContainsAny(alias, "@/ ") {
+			return fmt.Errorf("invalid alias %q", alias)
+		}
+	}
+	if c.VerifierFile != "" && len(c.VerifierRunCmd) == 0 {
+		return errors.New("verifier_file is set without verifier_run")
+	}
+	for _, name := range []string{c.CodeFile, c.VerifierFile} {
+		if name != "" && (strings.ContainsAny(name, "/\\") || name == "." || name == "..") {
+			return fmt.Errorf("invalid file name %q", name)
+		}
+	}
+	for _, env := range []map[string]string{c.Env, c.RunEnv} {
+		for key := range env {
+			if key == "" || strings.ContainsAny(key, "=\x00") {
+				return fmt.Errorf("invalid environment variable %q", key)
+			}
+		}
+	}
+	for _, m := range slices.Concat(c.Mounts, c.Caches) {
+		if !path.IsAbs(m.Source) || !path.IsAbs(m.Target) || path.Clean(m.Target) != m.Target || m.Target == "/" {
+			return fmt.Errorf("invalid mount %s -> %s, absolute paths are required", m.Source, m.Target)
+		}
+		if m.Target == "/w" || m.Target == "/tmp" || m.Target == "/proc" || strings.HasPrefix(m.Target, "/dev") {
+			return fmt.Errorf("mount target %s is reserved", m.Target)
+		}
+	}
+	for _, m := range c.Mounts {
+		if len(m.Warmup) > 0 {
+			return fmt.Errorf("mount %s: warmup is supported by caches only", m.Target)
+		}
+	}
+	for _, m := range c.Caches {
+		if len(m.Warmup) > 0 {
+			if err := checkBinary(m.Warmup[0]); err != nil {
+				return fmt.Errorf("cache %s warmup: %w", m.Target, err)
+			}
+		}
+	}
+	if c.MemoryMultiplier < 0 {
+		return errors.New("memory_multiplier must not be negative")
+	}
+	if c.BuildMemoryLimit < 0 || c.BuildTimeout < 0 || c.BuildMaxFileSize < 0 {
+		return errors.New("build limits must not be negative")
+	}
+	if (len(c.BuildCmd) > 0 || len(c.VerifierBuildCmd) > 0) && (c.BuildMemoryLimit == 0 || c.BuildTimeout == 0 || c.BuildMaxFileSize == 0) {
+		return errors.New("build_memory_limit, build_timeout and build_max_file_size are required with build commands")
+	}
+	for _, cmd := range [][]string{c.BuildCmd, c.RunCmd, c.VerifierBuildCmd, c.VerifierRunCmd} {
+		if len(cmd) > 0 {
+			if err := checkBinary(cmd[0]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkBinary makes sure an absolute command exists on the host, host /usr and /bin
+// are mounted into containers. Relative commands are built by the attempt.
+func checkBinary(name string) error {
+	if !path.IsAbs(name) {
+		return nil
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("binary %s: %w", name, err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("binary %s is not executable", name)
+	}
+	return nil
+}
+
+// environ returns the environment of build commands or, if run is set, of run commands
+func (c *languageConfig) environ(run bool) []string {
+	vars := map[string]string{"PATH": defaultPath}
+	maps.Copy(vars, c.Env)
+	if run {
+		maps.Copy(vars, c.RunEnv)
+	}
+	env := make([]string, 0, len(vars))
+	for key, value := range vars {
+		env = append(env, key+"="+value)
+	}
+	slices.Sort(env)
+	return env
+}
+
+// memoryLimit returns the memory limit of test runs for the limit of the attempt
+func (c *languageConfig) memoryLimit(limit int64) int64 {
+	if c.MemoryMultiplier == 0 || limit == 0 {
+		return limit
+	}
+	return int64(float64(limit) * c.MemoryMultiplier)
+}
+
+// runParams fills parameters set by the language for running cmd
+func (c *languageConfig) runParams(cenv container.Environment, cmd []string, run bool) RunParams {
+	return RunParams{
+		ContainerEnv: cenv,
+		Args:         cmd,
+		Env:          c.environ(run),
+		StackLimit:   cmp.Or(c.StackLimit, defaultStackLimit),
+		OpenFiles:    cmp.Or(c.OpenFiles, defaultOpenFiles),
+	}
+}
+
+// withFiles returns copy of the config with glob patterns in commands expanded by
+// names of files in the working directory
+func (c *languageConfig) withFiles(names []string) *languageConfig {
+	res := *c
+	res.BuildCmd = expandArgs(c.BuildCmd, names)
+	res.RunCmd = expandArgs(c.RunCmd, names)
+	res.VerifierBuildCmd = expandArgs(c.VerifierBuildCmd, names)
+	res.VerifierRunCmd = expandArgs(c.VerifierRunCmd, names)
+	return &res
+}
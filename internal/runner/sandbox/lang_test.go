@@ -0,0 +1,64 @@
+package sandbox
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLanguageConfig_RunParams(t *testing.T) {
+	cfg := &languageConfig{
+		Env:              map[string]string{"JAVA_HOME": "/jdk", "PATH": "/jdk/bin:/usr/bin"},
+		RunEnv:           map[string]string{"JAVA_TOOL_OPTIONS": "-Xss64m"},
+		OpenFiles:        64,
+		MemoryMultiplier: 1.5,
+	}
+	build := cfg.runParams(nil, []string{"javac"}, false)
+	if !slices.Equal(build.Env, []string{"JAVA_HOME=/jdk", "PATH=/jdk/bin:/usr/bin"}) {
+		t.Errorf("Unexpected build env: %v", build.Env)
+	}
+	run := cfg.runParams(nil, []string{"java"}, true)
+	if !slices.Equal(run.Env, []string{"JAVA_HOME=/jdk", "JAVA_TOOL_OPTIONS=-Xss64m", "PATH=/jdk/bin:/usr/bin"}) {
+		t.Errorf("Unexpected run env: %v", run.Env)
+	}
+	if run.StackLimit != defaultStackLimit || run.OpenFiles != 64 {
+		t.Errorf("Unexpected limits: %d, %d", run.StackLimit, run.OpenFiles)
+	}
+	if limit := cfg.memoryLimit(100); limit != 150 {
+		t.Errorf("Unexpected memory limit: %d", limit)
+	}
+	if env := (&languageConfig{}).environ(true); !slices.Equal(env, []string{"PATH=" + defaultPath}) {
+		t.Errorf("Unexpected default env: %v", env)
+	}
+}
+
+func TestLanguageConfig_Mounts(t *testing.T) {
+	base := &languageConfig{RunCmd: []string{"./run"}}
+	java := &languageConfig{RunCmd: []string{"./run"}, Mounts: []mountConfig{{Source: "/etc/java21-openjdk", Target: "/etc/java21-openjdk"}}}
+	if base.profile() != "" || java.profile() == "" {
+		t.Fatalf("Unexpected profiles: %q, %q", base.profile(), java.profile())
+	}
+	if err := java.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	for _, m := range []mountConfig{{Source: "/x", Target: "/w"}, {Source: "/x", Target: "x"}, {Source: "x", Target: "/x"}, {Source: "/x", Target: "/a/../x"}} {
+		cfg := &languageConfig{RunCmd: []string{"./run"}, Caches: []mountConfig{m}}
+		if err := cfg.validate(); err == nil {
+			t.Errorf("Mount %+v must be rejected", m)
+		}
+	}
+}
+
+func TestCompileOutput(t *testing.T) {
+	if out := compileOutput([]byte("main.c:1: error\n"), []byte("note\n")); out != "main.c:1: error\nnote" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+	if out := compileOutput(nil, []byte("\xff")); out != "\uFFFD" {
+		t.Errorf("Invalid UTF-8 must be replaced: %q", out)
+	}
+	out := compileOutput(bytes.Repeat([]byte("a"), compileOutputLimit+10), nil)
+	if !strings.HasSuffix(out, "truncated") || len(out) > compileOutputLimit+32 {
+		t.Errorf("Output must be truncated, got %d bytes", len(out))
+	}
+}
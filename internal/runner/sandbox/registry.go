@@ -0,0 +1,260 @@
+package sandbox
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	rr "github.com/cutekitek/rankode-runner/internal/runner"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Changes are applied after the files stop changing for this long, editors and
+// deploys usually touch several files at once
+const reloadDelay = 500 * time.Millisecond
+
+// languageRegistry keeps parsed configs of the languages directory, a subdirectory
+// with config.json per language
+type languageRegistry struct {
+	dir string
+	mu  sync.RWMutex
+	// By directory name
+	langs map[string]*languageConfig
+	// By directory name and alias
+	index   map[string]*languageConfig
+	watcher *fsnotify.Watcher
+	// Called with the languages after each successful load
+	onLoad func(langs ...*languageConfig)
+}
+
+func newLanguageRegistry(dir string) *languageRegistry {
+	return &languageRegistry{dir: dir}
+}
+
+// load reads all languages and replaces the current ones at once. Invalid languages
+// are logged and skipped, so a broken config doesn't disable the others.
+func (l *languageRegistry) load() error {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read languages directory: %w", err)
+	}
+	langs := make(map[string]*languageConfig, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		cfg, err := NewLangConfigFromFile(filepath.Join(l.dir, e.Name()))
+		if err == nil {
+			err = cfg.validate()
+		}
+		if err != nil {
+			slog.Error("invalid language config", "language", e.Name(), "error", err)
+			continue
+		}
+		langs[e.Name()] = cfg
+	}
+	if len(langs) == 0 {
+		return fmt.Errorf("no valid languages in %s", l.dir)
+	}
+
+	index := indexLanguages(langs)
+	l.mu.Lock()
+	l.langs = langs
+	l.index = index
+	l.mu.Unlock()
+	slog.Info("languages loaded", "languages", l.names())
+	if l.onLoad != nil {
+		l.onLoad(slices.Collect(maps.Values(langs))...)
+	}
+	return nil
+}
+
+// get finds a language by directory name or alias. A version may be requested as
+// `name@version`, e.g. python@3.12, it matches equal versions and their prefixes
+// ending at a dot, so python@3 matches 3.12 too.
+func (l *languageRegistry) get(name string) (*languageConfig, error) {
+	key, version, versioned := strings.Cut(name, "@")
+	l.mu.RLock()
+	var cfg *languageConfig
+	if !versioned {
+		cfg = l.index[key]
+	} else {
+		var ids []string
+		for id, c := range l.langs {
+			if (id == key || slices.Contains(c.Aliases, key)) && versionMatches(c.Version, version) {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			if cfg == nil || l.langs[id].Default && !cfg.Default {
+				cfg = l.langs[id]
+			}
+		}
+	}
+	l.mu.RUnlock()
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: unknown language %q, available: %s", rr.ErrInvalidRequest, name, strings.Join(l.names(), ", "))
+	}
+	return cfg, nil
+}
+
+func versionMatches(version, requested string) bool {
+	return version == requested || strings.HasPrefix(version, requested+".")
+}
+
+// indexLanguages maps directory names and aliases to languages. An alias of several
+// languages refers to the default one of them, directory names take precedence.
+func indexLanguages(langs map[string]*languageConfig) map[string]*languageConfig {
+	claims := make(map[string][]string)
+	for id, cfg := range langs {
+		for _, alias := range cfg.Aliases {
+			if _, ok := langs[alias]; !ok {
+				claims[alias] = append(claims[alias], id)
+			}
+		}
+	}
+	index := make(map[string]*languageConfig, len(langs)+len(claims))
+	for id, cfg := range langs {
+		index[id] = cfg
+	}
+	for alias, ids := range claims {
+		if len(ids) == 1 {
+			index[alias] = langs[ids[0]]
+			continue
+		}
+		var defaults []string
+		for _, id := range ids {
+			if langs[id].Default {
+				defaults = append(defaults, id)
+			}
+		}
+		if len(defaults) != 1 {
+			slices.Sort(ids)
+			slog.Error("ambiguous language alias, mark one language as default", "alias", alias, "languages", ids)
+			continue
+		}
+		index[alias] = langs[defaults[0]]
+	}
+	return index
+}
+
+// names returns sorted names of loaded languages
+func (l *languageRegistry) names() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.langs))
+	for name := range l.langs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// configs returns loaded languages
+func (l *languageRegistry) configs() []*languageConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Collect(maps.Values(l.langs))
+}
+
+// list describes loaded languages sorted by directory name
+func (l *languageRegistry) list() []models.Language {
+	names := l.names()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	res := make([]models.Language, 0, len(names))
+	for _, name := range names {
+		cfg, ok := l.langs[name]
+		if !ok {
+			continue
+		}
+		res = append(res, models.Language{
+			ImageName: name,
+			Name:      cmp.Or(cfg.Name, name),
+			Version:   cfg.Version,
+			Aliases:   cfg.Aliases,
+			Extension: cfg.Extension,
+			Default:   cfg.Default,
+		})
+	}
+	return res
+}
+
+// watch reloads languages when files in the directory change until close is called
+func (l *languageRegistry) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	l.watcher = watcher
+	if err := l.addWatches(); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		timer := time.NewTimer(reloadDelay)
+		timer.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					timer.Stop()
+					return
+				}
+				slog.Debug("languages directory changed", "event", event)
+				timer.Reset(reloadDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					timer.Stop()
+					return
+				}
+				slog.Error("languages watcher error", "error", err)
+			case <-timer.C:
+				// New language directories must be watched too
+				if err := l.addWatches(); err != nil {
+					slog.Error("failed to watch languages", "error", err)
+				}
+				if err := l.load(); err != nil {
+					slog.Error("failed to reload languages", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// addWatches watches the languages directory and its subdirectories, watches of
+// removed directories are dropped by fsnotify
+func (l *languageRegistry) addWatches() error {
+	if err := l.watcher.Add(l.dir); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := l.watcher.Add(filepath.Join(l.dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (l *languageRegistry) close() {
+	if l.watcher != nil {
+		l.watcher.Close()
+	}
+}
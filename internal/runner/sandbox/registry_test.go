@@ -0,0 +1,98 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/runner"
+)
+
+func writeLanguage(t *testing.T, dir, name, config string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLanguageRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeLanguage(t, dir, "sh", `{"run": ["/bin/sh", "code.sh"], "codefile": "code.sh"}`)
+	writeLanguage(t, dir, "no-run", `{"codefile": "code"}`)
+	writeLanguage(t, dir, "missing", `{"run": ["/usr/bin/no-such-binary"]}`)
+	writeLanguage(t, dir, "no-limits", `{"build": ["/bin/sh"], "run": ["./run"]}`)
+
+	reg := newLanguageRegistry(dir)
+	if err := reg.load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if names := reg.names(); !slices.Equal(names, []string{"sh"}) {
+		t.Fatalf("Invalid languages must be skipped, got %v", names)
+	}
+	if _, err := reg.get("cobol"); !errors.Is(err, runner.ErrInvalidRequest) {
+		t.Fatalf("Unknown language must be an invalid request, got %v", err)
+	}
+
+	if err := reg.watch(); err != nil {
+		t.Fatal(err)
+	}
+	defer reg.close()
+	writeLanguage(t, dir, "sh2", `{"run": ["/bin/sh", "code.sh"]}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(reg.names(), "sh2") {
+		if time.Now().After(deadline) {
+			t.Fatal("New language is not loaded")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestLanguageRegistry_Aliases(t *testing.T) {
+	dir := t.TempDir()
+	writeLanguage(t, dir, "python3", `{"run": ["/bin/sh"], "version": "3.12.4", "aliases": ["python", "py"], "default": true}`)
+	writeLanguage(t, dir, "pypy3", `{"run": ["/bin/sh"], "version": "3.10", "aliases": ["python", "pypy"]}`)
+	writeLanguage(t, dir, "sh", `{"run": ["/bin/sh"], "aliases": ["bash", "shell"]}`)
+	writeLanguage(t, dir, "dash", `{"run": ["/bin/sh"], "aliases": ["shell"]}`)
+
+	reg := newLanguageRegistry(dir)
+	if err := reg.load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	tests := map[string]string{
+		"python3":     "python3",
+		"py":          "python3",
+		"python":      "python3",
+		"pypy":        "pypy3",
+		"python@3.10": "pypy3",
+		"python@3.12": "python3",
+		"python@3":    "python3",
+		"pypy3@3":     "pypy3",
+		"bash":        "sh",
+	}
+	for name, expected := range tests {
+		cfg, err := reg.get(name)
+		if err != nil {
+			t.Errorf("%s: get failed: %v", name, err)
+			continue
+		}
+		if cfg != reg.langs[expected] {
+			t.Errorf("%s: expected %s", name, expected)
+		}
+	}
+	for _, name := range []string{"shell", "python@3.1", "ruby"} {
+		if _, err := reg.get(name); !errors.Is(err, runner.ErrInvalidRequest) {
+			t.Errorf("%s must not be found, got %v", name, err)
+		}
+	}
+
+	list := reg.list()
+	if len(list) != 4 || list[2].ImageName != "python3" || list[2].Version != "3.12.4" || list[2].Name != "python3" || !list[2].Default {
+		t.Fatalf("Unexpected languages: %+v", list)
+	}
+}
@@ -2,14 +2,12 @@ package sandbox
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"os/exec"
-	"path"
-	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -18,22 +16,21 @@ import (
 
 	"github.com/criyle/go-sandbox/container"
 	"github.com/criyle/go-sandbox/pkg/cgroup"
-	"github.com/criyle/go-sandbox/pkg/mount"
 	"github.com/criyle/go-sandbox/pkg/rlimit"
 	"github.com/criyle/go-sandbox/runner"
 	"github.com/cutekitek/rankode-runner/internal/repository/dto"
 	"github.com/cutekitek/rankode-runner/internal/repository/models"
 	"github.com/pkg/errors"
-	"golang.org/x/sys/unix"
 )
 
 const (
-	goCacheShared = "/tmp/rankode-gocache-shared"
+	buildOutputLimit = 1024 * 1024
+	// Compiler output returned with the attempt is cut to this size
+	compileOutputLimit = 64 * 1024
 )
 
 var (
-	rootCG      cgroup.Cgroup
-	goCacheOnce sync.Once
+	rootCG cgroup.Cgroup
 )
 
 func init() {
@@ -62,11 +59,20 @@ type SandboxRunnerConfig struct {
 type sandboxContainerEnv struct {
 	container.Environment
 	WorkDir string
+	// Mounts of the container, see languageConfig.profile
+	profile string
 }
 
 type SandboxRunner struct {
-	Config     SandboxRunnerConfig
-	containers chan *sandboxContainerEnv
+	Config SandboxRunnerConfig
+	// Holds a value for every running attempt, ContainersPoolSize attempts run at once
+	slots chan struct{}
+	mu    sync.Mutex
+	// Idle containers by profile
+	idle      map[string]chan *sandboxContainerEnv
+	languages *languageRegistry
+	// Host directories of warmed caches
+	warmed sync.Map
 }
 
 type containerRunner struct {
@@ -79,71 +85,88 @@ func (r *containerRunner) Run(c context.Context) runner.Result {
 }
 
 func NewSandboxRunner(cfg SandboxRunnerConfig) *SandboxRunner {
-	return &SandboxRunner{
-		Config:     cfg,
-		containers: make(chan *sandboxContainerEnv, cfg.ContainersPoolSize),
-	}
+	r := &SandboxRunner{
+		Config:    cfg,
+		slots:     make(chan struct{}, cfg.ContainersPoolSize),
+		idle:      make(map[string]chan *sandboxContainerEnv),
+		languages: newLanguageRegistry(cfg.RunnerScriptsPath),
+	}
+	// Caches of languages added by reload are warmed without blocking attempts of
+	// other languages
+	r.languages.onLoad = r.warmCachesAsync
+	return r
 }
 
 func (r *SandboxRunner) Init() error {
-	r.initSharedGoCache()
+	if err := r.languages.load(); err != nil {
+		return err
+	}
+	// Reload by SIGHUP still works without the watcher
+	if err := r.languages.watch(); err != nil {
+		slog.Warn("failed to watch languages directory", "error", err)
+	}
+	r.warmCaches(r.languages.configs()...)
 	return r.prepareContainers()
 }
 
-func (r *SandboxRunner) initSharedGoCache() {
-	goCacheOnce.Do(func() {
-		if _, err := os.Stat(goCacheShared); os.IsNotExist(err) {
-			if err := os.MkdirAll(goCacheShared, 0777); err != nil {
-				slog.Error("failed to create shared Go cache directory", "error", err)
-				return
-			}
-		}
-		goPath, err := exec.LookPath("go")
-		if err != nil {
-			slog.Info("go binary not found, skipping shared cache population")
-			return
-		}
-		slog.Info("Populating shared Go cache...", "path", goCacheShared)
-		cmd := exec.Command(goPath, "build", "std")
-		cmd.Env = append(os.Environ(), "GOCACHE="+goCacheShared)
-		if out, err := cmd.CombinedOutput(); err != nil {
-			slog.Error("failed to populate shared Go cache", "error", err, "output", string(out))
-		} else {
-			slog.Info("Shared Go cache populated successfully")
-		}
-		// Recursive chmod to ensure container user can read/traverse
-		if err := exec.Command("chmod", "-R", "777", goCacheShared).Run(); err != nil {
-			slog.Error("failed to chmod shared Go cache", "error", err)
-		}
-		slog.Info("Shared Go cache populated successfully", "path", goCacheShared)
-	})
+// Reload rereads language configs, running attempts keep their configs. New caches
+// are warmed in the background.
+func (r *SandboxRunner) Reload() error {
+	return r.languages.load()
+}
+
+// Languages describes available languages sorted by name
+func (r *SandboxRunner) Languages() []models.Language {
+	return r.languages.list()
 }
 
 func (r *SandboxRunner) Close() {
-	closed := 0
-	for closed < r.Config.ContainersPoolSize {
-		c := <-r.containers
-		c.Destroy()
-		os.Remove(c.WorkDir)
-		closed++
+	r.languages.close()
+	// Wait for running attempts
+	for i := 0; i < r.Config.ContainersPoolSize; i++ {
+		r.slots <- struct{}{}
 	}
+	r.destroyIdle()
 }
 
-func (r *SandboxRunner) Run(req *dto.RunRequest) (*dto.RunResult, error) {
-	langConfig, err := r.getLangConfig(req)
+func (r *SandboxRunner) Run(ctx context.Context, req *dto.RunRequest) (*dto.RunResult, error) {
+	langConfig, err := r.getLangConfig(req.Image)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get language config")
+		return nil, err
+	}
+	langs := []*languageConfig{langConfig}
+	if req.Checker != nil {
+		checkerLang, err := r.getLangConfig(req.Checker.Image)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get checker language config")
+		}
+		langs = append(langs, checkerLang)
+	}
+	// Without a slot, so attempts of other languages are not blocked
+	if err := r.waitWarmup(ctx, langs...); err != nil {
+		return nil, err
 	}
 
-	container := <-r.containers
+	if err := r.acquireSlot(ctx); err != nil {
+		return nil, err
+	}
+	defer r.releaseSlot()
+	container, err := r.getContainer(langConfig)
+	if err != nil {
+		return nil, err
+	}
 	container.Reset()
 	defer func() {
-		r.containers <- container
+		if container != nil {
+			r.putContainer(container)
+		}
 	}()
 
-	if err := r.initFiles(req, container, langConfig); err != nil {
+	names, err := r.initFiles(ctx, req, container, langConfig)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to init files")
 	}
+	langConfig = langConfig.withFiles(names)
 
 	if err := container.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping container: %w", err)
@@ -155,123 +178,156 @@ func (r *SandboxRunner) Run(req *dto.RunRequest) (*dto.RunResult, error) {
 		buildCmd = langConfig.VerifierBuildCmd
 	}
 
+	var buildOutput string
 	if len(buildCmd) > 0 {
-		if err := r.build(langConfig, container, buildCmd); err != nil {
-			if res, ok := err.(*runFailedError); ok {
+		emit(req, dto.RunEvent{Type: models.AttemptEventCompiling})
+		buildOutput, err = r.build(ctx, langConfig, container, buildCmd)
+		if err != nil {
+			var buildErr *buildFailedError
+			if errors.As(err, &buildErr) {
 				return &dto.RunResult{
-					Status: models.AttemptStatusBuildFailed,
-					Error:  res.ErrorLogs + err.Error(),
+					Status:        models.AttemptStatusBuildFailed,
+					Error:         buildErr.Error(),
+					CompileOutput: buildOutput,
 				}, nil
 			}
 			return nil, errors.Wrap(err, "build failed")
 		}
+		emit(req, dto.RunEvent{Type: models.AttemptEventCompiled})
 	}
 
 	// Run test cases or verification
+	var result *dto.RunResult
 	if req.VerificationCode != "" {
-		return r.runVerification(req, container, langConfig)
+		result, err = r.runVerification(ctx, req, container, langConfig)
+	} else {
+		result, err = r.runTestCases(ctx, req, container, langConfig)
 	}
-
-	return r.runTestCases(req, container, langConfig)
-}
-
-func (r *SandboxRunner) getLangConfig(req *dto.RunRequest) (*languageConfig, error) {
-	path := filepath.Join(r.Config.RunnerScriptsPath, req.Image)
-	cfg, err := NewLangConfigFromFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, errors.New("language not found")
-		}
 		return nil, err
 	}
-	return cfg, nil
+	result.CompileOutput = buildOutput
+	if req.VerificationCode != "" || req.Checker == nil {
+		return result, nil
+	}
+	// The checker language may need other mounts, so it gets its own container
+	r.putContainer(container)
+	container = nil
+	if err := r.runChecker(ctx, req, result); err != nil {
+		return nil, errors.Wrap(err, "failed to check outputs")
+	}
+	return result, nil
 }
 
-func (r *SandboxRunner) initFiles(req *dto.RunRequest, env container.Environment, lang *languageConfig) error {
-	codeFile := "/w/code"
-	if lang.CodeFile != "" {
-		codeFile = "/w/" + lang.CodeFile
+func emit(req *dto.RunRequest, event dto.RunEvent) {
+	if req.OnEvent != nil {
+		req.OnEvent(event)
 	}
+}
 
-	openCmds := []container.OpenCmd{
-		{Path: codeFile, Flag: os.O_WRONLY | os.O_CREATE | os.O_TRUNC, Perm: 0777},
-	}
+func (r *SandboxRunner) getLangConfig(image string) (*languageConfig, error) {
+	return r.languages.get(image)
+}
 
-	if req.VerificationCode != "" && lang.VerifierFile != "" {
-		openCmds = append(openCmds, container.OpenCmd{
-			Path: "/w/" + lang.VerifierFile,
-			Flag: os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
-			Perm: 0777,
-		})
+// initFiles writes source files and attachments of the request to /w and returns their names
+func (r *SandboxRunner) initFiles(ctx context.Context, req *dto.RunRequest, env container.Environment, lang *languageConfig) ([]string, error) {
+	files, err := sourceFiles(req, lang)
+	if err != nil {
+		return nil, err
 	}
-
-	files, err := env.Open(openCmds)
+	attachments, err := attachmentFiles(req, files)
 	if err != nil {
-		return fmt.Errorf("failed to open files in container: %w", err)
+		return nil, err
 	}
-	defer func() {
-		for _, f := range files {
-			f.Close()
-		}
-	}()
-
-	if _, err := io.Copy(files[0], strings.NewReader(req.Code)); err != nil {
-		return fmt.Errorf("failed to copy code file: %w", err)
+	if err := checkIOFiles(req, files, attachments); err != nil {
+		return nil, err
 	}
-
-	if req.VerificationCode != "" && lang.VerifierFile != "" {
-		if _, err := io.Copy(files[1], strings.NewReader(req.VerificationCode)); err != nil {
-			return fmt.Errorf("failed to copy verification file: %w", err)
+	if err := r.makeDirs(ctx, env, files, attachments); err != nil {
+		return nil, err
+	}
+	if err := writeFiles(env, files, 0777); err != nil {
+		return nil, err
+	}
+	// Owned by the container init user, so processes of the attempt can't modify them.
+	// /w is sticky, so they can't be removed or renamed either, unless they are in a
+	// subdirectory, which is created by the sandboxed user.
+	if len(attachments) > 0 {
+		if err := writeFiles(env, attachments, 0444); err != nil {
+			return nil, err
 		}
 	}
-
-	return nil
+	names := make([]string, 0, len(files)+len(attachments))
+	for name := range files {
+		names = append(names, name)
+	}
+	for name := range attachments {
+		names = append(names, name)
+	}
+	return names, nil
 }
 
-type runFailedError struct {
-	ErrorLogs  string
-	StatusCode int
+// buildFailedError is returned by build when the compiler rejects the code. The
+// message describes only how the compiler ended, its output is returned separately.
+type buildFailedError struct {
+	Status     runner.Status
+	ExitStatus int
 }
 
-func (r *runFailedError) Error() string {
-	return fmt.Sprintf("failed to run(%d)", r.StatusCode)
+func (e *buildFailedError) Error() string {
+	if e.Status == runner.StatusNormal || e.Status == runner.StatusNonzeroExitStatus {
+		return fmt.Sprintf("compiler exited with code %d", e.ExitStatus)
+	}
+	return fmt.Sprintf("compiler failed: %s", e.Status)
 }
 
-func (r *SandboxRunner) build(cfg *languageConfig, cenv container.Environment, args []string) error {
-	params := RunParams{
-		ContainerEnv: cenv,
-		Args:         args,
-		MaxFileSize:  int64(cfg.BuildMaxFileSize),
-		Timeout:      cfg.BuildTimeout,
-		MemoryLimit:  int64(cfg.BuildMemoryLimit),
+// build runs the build command and returns the compiler output, also when the build
+// fails. Sources are compiled in /w by relative names, so paths in diagnostics are
+// the same for every run.
+func (r *SandboxRunner) build(ctx context.Context, cfg *languageConfig, cenv container.Environment, args []string) (string, error) {
+	params := cfg.runParams(cenv, args, false)
+	params.MaxFileSize = int64(cfg.BuildMaxFileSize)
+	params.Timeout = cfg.BuildTimeout
+	params.MemoryLimit = int64(cfg.BuildMemoryLimit)
+	// Compilers print warnings and notes, only flooding the output fails the build
+	params.MaxOutputSize = buildOutputLimit
+
+	res, err := r.ExecuteInSandbox(ctx, params)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to execute builder")
 	}
 
-	res, err := r.ExecuteInSandbox(params)
-	if err != nil {
-		return errors.Wrap(err, "failed to execute builder")
+	output := compileOutput(res.Error, res.Output)
+	if res.Status != runner.StatusNormal || res.ExitStatus != 0 {
+		return output, &buildFailedError{Status: res.Status, ExitStatus: res.ExitStatus}
 	}
+	return output, nil
+}
 
-	if res.Status != runner.StatusNormal {
-		return &runFailedError{ErrorLogs: string(res.Error), StatusCode: res.ExitStatus}
+// compileOutput joins stderr and stdout of the compiler and cuts the result to
+// compileOutputLimit, invalid UTF-8 is replaced as the output is sent as text
+func compileOutput(stderr, stdout []byte) string {
+	out := strings.TrimRight(string(stderr), "\n")
+	if s := strings.TrimRight(string(stdout), "\n"); s != "" {
+		if out != "" {
+			out += "\n"
+		}
+		out += s
 	}
-	if res.ExitStatus != 0 {
-		return &runFailedError{ErrorLogs: string(res.Error), StatusCode: res.ExitStatus}
+	if len(out) > compileOutputLimit {
+		out = out[:compileOutputLimit] + "\n... output truncated"
 	}
-
-	return nil
+	return strings.ToValidUTF8(out, "\uFFFD")
 }
 
-func (r *SandboxRunner) runVerification(req *dto.RunRequest, cenv container.Environment, cfg *languageConfig) (*dto.RunResult, error) {
-	params := RunParams{
-		ContainerEnv:  cenv,
-		Args:          cfg.VerifierRunCmd,
-		MaxFileSize:   int64(req.MaxOutputSize),
-		Timeout:       req.Timeout,
-		MemoryLimit:   int64(req.MemoryLimit),
-		MaxOutputSize: int64(req.MaxOutputSize),
-	}
+func (r *SandboxRunner) runVerification(ctx context.Context, req *dto.RunRequest, cenv container.Environment, cfg *languageConfig) (*dto.RunResult, error) {
+	params := cfg.runParams(cenv, cfg.VerifierRunCmd, true)
+	params.MaxFileSize = int64(req.MaxOutputSize)
+	params.Timeout = req.Timeout
+	params.MemoryLimit = cfg.memoryLimit(int64(req.MemoryLimit))
+	params.MaxOutputSize = int64(req.MaxOutputSize)
 
-	res, err := r.ExecuteInSandbox(params)
+	emit(req, dto.RunEvent{Type: models.AttemptEventTestStarted})
+	res, err := r.ExecuteInSandbox(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute verifier")
 	}
@@ -307,29 +363,45 @@ func (r *SandboxRunner) runVerification(req *dto.RunRequest, cenv container.Envi
 		result.Status = models.AttemptStatusRunFailed
 	}
 
+	emit(req, dto.RunEvent{Type: models.AttemptEventTestFinished, Status: caseStatus.Status, ExecutionTime: caseStatus.ExecutionTime})
 	result.Output = append(result.Output, caseStatus)
 	return result, nil
 }
 
-func (r *SandboxRunner) runTestCases(req *dto.RunRequest, cenv container.Environment, cfg *languageConfig) (*dto.RunResult, error) {
+func (r *SandboxRunner) runTestCases(ctx context.Context, req *dto.RunRequest, cenv container.Environment, cfg *languageConfig) (*dto.RunResult, error) {
 	result := &dto.RunResult{
 		Status: models.AttemptStatusSuccessful,
 	}
-	for _, input := range req.Input {
-		params := RunParams{
-			ContainerEnv:  cenv,
-			Args:          cfg.RunCmd,
-			MaxFileSize:   int64(req.MaxOutputSize),
-			Timeout:       req.Timeout,
-			MemoryLimit:   int64(req.MemoryLimit),
-			Input:         input,
-			MaxOutputSize: int64(req.MaxOutputSize),
+	for i, input := range req.Input {
+		params := cfg.runParams(cenv, cfg.RunCmd, true)
+		params.MaxFileSize = int64(req.MaxOutputSize)
+		params.Timeout = req.Timeout
+		params.MemoryLimit = cfg.memoryLimit(int64(req.MemoryLimit))
+		params.Input = input
+		params.MaxOutputSize = int64(req.MaxOutputSize)
+
+		if err := prepareIOFiles(req, cenv, input); err != nil {
+			return nil, err
+		}
+		if req.InputFile != "" {
+			params.Input = ""
 		}
 
-		res, err := r.ExecuteInSandbox(params)
+		emit(req, dto.RunEvent{Type: models.AttemptEventTestStarted, Test: i})
+		res, err := r.ExecuteInSandbox(ctx, params)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to execute runner")
 		}
+		if req.OutputFile != "" && res.Status == runner.StatusNormal {
+			res.Output, err = readFile(cenv, req.OutputFile, int64(req.MaxOutputSize))
+			if errors.Is(err, errFileTooLarge) {
+				res.Status = runner.StatusOutputLimitExceeded
+			} else if err != nil {
+				// The solution removed or replaced the file, that's an empty output
+				slog.Debug("failed to read output file", "error", err)
+				res.Output = nil
+			}
+		}
 
 		caseStatus := dto.RunCaseResult{
 			Output:        string(res.Output),
@@ -353,16 +425,34 @@ func (r *SandboxRunner) runTestCases(req *dto.RunRequest, cenv container.Environ
 			}
 			result.Error = string(res.Error)
 			result.Status = models.AttemptStatusRunFailed
+			emit(req, dto.RunEvent{Type: models.AttemptEventTestFinished, Test: i, Status: caseStatus.Status, ExecutionTime: caseStatus.ExecutionTime})
 			result.Output = append(result.Output, caseStatus)
 			return result, nil
 		}
 
+		emit(req, dto.RunEvent{Type: models.AttemptEventTestFinished, Test: i, Status: caseStatus.Status, ExecutionTime: caseStatus.ExecutionTime})
 		result.Output = append(result.Output, caseStatus)
 	}
 
 	return result, nil
 }
 
+// prepareIOFiles writes the test input and an empty output file of file I/O, so
+// outputs of previous tests are not taken for the output of this one
+func prepareIOFiles(req *dto.RunRequest, cenv container.Environment, input string) error {
+	files := make(map[string]string, 2)
+	if req.InputFile != "" {
+		files[req.InputFile] = input
+	}
+	if req.OutputFile != "" {
+		files[req.OutputFile] = ""
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	return writeFiles(cenv, files, 0777)
+}
+
 type RunParams struct {
 	ContainerEnv  container.Environment
 	Args          []string
@@ -371,6 +461,10 @@ type RunParams struct {
 	MemoryLimit   int64
 	Input         string
 	MaxOutputSize int64
+	// Only PATH is set if empty
+	Env        []string
+	StackLimit uint64
+	OpenFiles  uint64
 }
 
 type executionResult struct {
@@ -382,7 +476,9 @@ type executionResult struct {
 	Output     []byte
 }
 
-func (r *SandboxRunner) ExecuteInSandbox(params RunParams) (*executionResult, error) {
+// ExecuteInSandbox runs the command in the container. If parent is cancelled the process
+// is killed and the context error is returned.
+func (r *SandboxRunner) ExecuteInSandbox(parent context.Context, params RunParams) (*executionResult, error) {
 	var err error
 	var cg cgroup.Cgroup
 
@@ -401,7 +497,7 @@ func (r *SandboxRunner) ExecuteInSandbox(params RunParams) (*executionResult, er
 		return nil, fmt.Errorf("failed to open cg fd: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), params.Timeout)
+	ctx, cancel := context.WithTimeout(parent, params.Timeout)
 	defer cancel()
 
 	stdinR, stdinW, _ := os.Pipe()
@@ -430,15 +526,20 @@ func (r *SandboxRunner) ExecuteInSandbox(params RunParams) (*executionResult, er
 		CPU:      uint64(params.Timeout.Seconds()) + 1,
 		CPUHard:  uint64(params.Timeout.Seconds()) + 2,
 		FileSize: uint64(params.MaxFileSize),
-		Stack:    128 * 1024 * 1024,
-		OpenFile: 2048,
+		Stack:    cmp.Or(params.StackLimit, defaultStackLimit),
+		OpenFile: cmp.Or(params.OpenFiles, defaultOpenFiles),
+	}
+
+	env := params.Env
+	if len(env) == 0 {
+		env = []string{"PATH=" + defaultPath}
 	}
 
 	rs := containerRunner{
 		Environment: params.ContainerEnv,
 		ExecveParam: container.ExecveParam{
 			Args:     params.Args,
-			Env:      []string{"PATH=/usr/local/bin:/usr/bin:/bin", "GOCACHE=/gocache", "JAVA_HOME=/etc/java21-openjdk", "GO111MODULE=off"},
+			Env:      env,
 			Files:    []uintptr{stdinR.Fd(), stdoutW.Fd(), stderrW.Fd()},
 			RLimits:  rlims.PrepareRLimit(),
 			SyncFunc: syncFunc,
@@ -455,6 +556,10 @@ func (r *SandboxRunner) ExecuteInSandbox(params RunParams) (*executionResult, er
 	stderrW.Close()
 	wg.Wait()
 
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+
 	execRes := &executionResult{
 		Status:     res.Status,
 		ExitStatus: res.ExitStatus,
@@ -520,95 +625,6 @@ func pipeWriter(ctx context.Context, pipe *os.File, in string) {
 	}
 }
 
-func (r *SandboxRunner) PrepareContainer(workdir string) (container.Environment, error) {
-	goCacheUpper := filepath.Join(workdir, "gocache-upper")
-	goCacheWork := filepath.Join(workdir, "gocache-work")
-	overlayData := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s,userxattr,index=off", goCacheShared, goCacheUpper, goCacheWork)
-
-	mb := mount.NewBuilder().
-		WithBind("/bin", "bin", true).
-		WithBind("/lib", "lib", true).
-		WithBind("/lib64", "lib64", true).
-		WithBind("/usr", "usr", true).
-		WithBind("/etc/ld.so.cache", "etc/ld.so.cache", true).
-		WithBind("/etc/alternatives", "etc/alternatives", true).
-		WithBind("/etc/java21-openjdk", "etc/java21-openjdk", true).
-		WithBind("/etc/ssl", "etc/ssl", true).                         // SSL Certs
-		WithBind("/etc/pki", "etc/pki", true).                         // CA Certs (RedHat/Fedora)
-		WithBind("/etc/crypto-policies", "etc/crypto-policies", true). // Crypto policies
-		WithBind("/etc/ca-certificates", "etc/ca-certificates", true). // Ubuntu/Debian Certs
-		WithProc().
-		WithBind("/dev/null", "dev/null", false).
-		WithBind("/dev/urandom", "dev/urandom", false).
-		WithBind("/dev/random", "dev/random", false).
-		WithMount(mount.Mount{
-			Source: "overlay",
-			Target: "gocache",
-			FsType: "overlay",
-			Data:   overlayData,
-			Flags:  unix.MS_NOSUID | unix.MS_NODEV,
-		}).
-		WithTmpfs("tmp", "size=128m,nr_inodes=4k").
-		WithTmpfs("w", "size=32m,nr_inodes=4k").
-		FilterNotExist()
-
-	mounts := mb.FilterNotExist()
-
-	cloneFlag := unix.CLONE_NEWIPC | unix.CLONE_NEWNET | unix.CLONE_NEWNS | unix.CLONE_NEWPID | unix.CLONE_NEWUSER | unix.CLONE_NEWUTS
-
-	b := container.Builder{
-		Root:          path.Join(workdir, "root"),
-		WorkDir:       "/w",
-		Mounts:        mounts.Mounts,
-		Stderr:        os.Stderr,
-		CredGenerator: newCredGen(),
-		CloneFlags:    uintptr(cloneFlag),
-		SymbolicLinks: []container.SymbolicLink{
-			{LinkPath: "/dev/fd", Target: "/proc/self/fd"},
-			{LinkPath: "/dev/stdin", Target: "/proc/self/fd/0"},
-			{LinkPath: "/dev/stdout", Target: "/proc/self/fd/1"},
-			{LinkPath: "/dev/stderr", Target: "/proc/self/fd/2"},
-		},
-	}
-	return b.Build()
-}
-
-func (r *SandboxRunner) prepareContainers() error {
-	for i := 0; i < r.Config.ContainersPoolSize; i++ {
-		containerPath := fmt.Sprintf("/tmp/rankode-container-%d", i)
-		if _, err := os.Stat(containerPath); err != nil {
-			if err := os.Mkdir(containerPath, 0777); err != nil {
-				return fmt.Errorf("failed to create container dir: %w", err)
-			}
-			if err := os.Mkdir(path.Join(containerPath, "root"), 0777); err != nil {
-				return fmt.Errorf("failed to create container root: %w", err)
-			}
-			if err := os.Mkdir(path.Join(containerPath, "root", "gocache"), 0777); err != nil {
-				return fmt.Errorf("failed to create container root gocache: %w", err)
-			}
-			if err := os.Mkdir(path.Join(containerPath, "gocache-upper"), 0777); err != nil {
-				return fmt.Errorf("failed to create container gocache-upper: %w", err)
-			}
-			os.Chmod(path.Join(containerPath, "gocache-upper"), 0777)
-			if err := os.Mkdir(path.Join(containerPath, "gocache-work"), 0777); err != nil {
-				return fmt.Errorf("failed to create container gocache-work: %w", err)
-			}
-			os.Chmod(path.Join(containerPath, "gocache-work"), 0777)
-		}
-
-		c, err := r.PrepareContainer(containerPath)
-
-		if err != nil {
-			return errors.Wrap(err, "failed to create container")
-		}
-		r.containers <- &sandboxContainerEnv{
-			Environment: c,
-			WorkDir:     containerPath,
-		}
-	}
-	return nil
-}
-
 type credGen struct {
 	cur uint32
 }
@@ -1,14 +1,18 @@
 package sandbox
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cutekitek/rankode-runner/internal/repository/dto"
 	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/runner"
 )
 
 var sbRunner *SandboxRunner
@@ -97,6 +101,31 @@ func main() {
 			code:     `print("Hello, World!")`,
 			expected: "Hello, World!\n",
 		},
+		{
+			language: "rust",
+			code:     `fn main() { println!("Hello, World!"); }`,
+			expected: "Hello, World!\n",
+		},
+		{
+			language: "csharp",
+			code:     `Console.WriteLine("Hello, World!");`,
+			expected: "Hello, World!\n",
+		},
+		{
+			language: "perl",
+			code:     `print "Hello, World!\n";`,
+			expected: "Hello, World!\n",
+		},
+		{
+			language: "kotlin",
+			code:     `fun main() { println("Hello, World!") }`,
+			expected: "Hello, World!\n",
+		},
+		{
+			language: "pypy3",
+			code:     `print("Hello, World!")`,
+			expected: "Hello, World!\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,7 +139,7 @@ func main() {
 				MaxFilesSize:  100 * 1024 * 1024, // 100MB
 				MaxOutputSize: 1024 * 1024,       // 1MB
 			}
-			res, err := sbRunner.Run(req)
+			res, err := sbRunner.Run(context.Background(), req)
 			if err != nil {
 				t.Fatalf("Run failed: %v", err)
 			}
@@ -185,6 +214,39 @@ func main() { fmt.Println(Add(2, 3)) }`,
 add 2 3`,
 			expected: "5\n",
 		},
+		{
+			language: "rust",
+			code:     "pub fn add(a: i32, b: i32) -> i32 { a + b }",
+			verificationCode: `include!("main.rs");
+fn main() { println!("{}", add(2, 3)); }`,
+			expected: "5\n",
+		},
+		{
+			language:         "csharp",
+			code:             "public static class Solution { public static int Add(int a, int b) => a + b; }",
+			verificationCode: `Console.WriteLine(Solution.Add(2, 3));`,
+			expected:         "5\n",
+		},
+		{
+			language: "perl",
+			code:     "sub add { return $_[0] + $_[1]; }",
+			verificationCode: `do "./main.pl";
+print add(2, 3), "\n";`,
+			expected: "5\n",
+		},
+		{
+			language:         "kotlin",
+			code:             "fun add(a: Int, b: Int) = a + b",
+			verificationCode: `fun main() { println(add(2, 3)) }`,
+			expected:         "5\n",
+		},
+		{
+			language: "pypy3",
+			code:     "def add(a, b):\n    return a + b",
+			verificationCode: `import solution
+print(solution.add(2, 3))`,
+			expected: "5\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -198,7 +260,7 @@ add 2 3`,
 				MaxFilesSize:     100 * 1024 * 1024,
 				MaxOutputSize:    1024 * 1024,
 			}
-			res, err := sbRunner.Run(req)
+			res, err := sbRunner.Run(context.Background(), req)
 			if err != nil {
 				t.Fatalf("Run failed: %v", err)
 			}
@@ -289,6 +351,46 @@ if data:
 			input:    []string{"5"},
 			expected: "10\n",
 		},
+		{
+			language: "rust",
+			code: `use std::io::Read;
+fn main() {
+    let mut s = String::new();
+    std::io::stdin().read_to_string(&mut s).unwrap();
+    let n: i64 = s.trim().parse().unwrap();
+    println!("{}", n * 2);
+}`,
+			input:    []string{"5"},
+			expected: "10\n",
+		},
+		{
+			language: "csharp",
+			code:     `Console.WriteLine(int.Parse(Console.ReadLine()) * 2);`,
+			input:    []string{"5"},
+			expected: "10\n",
+		},
+		{
+			language: "perl",
+			code: `my $n = <STDIN>;
+print $n * 2, "\n";`,
+			input:    []string{"5"},
+			expected: "10\n",
+		},
+		{
+			language: "kotlin",
+			code: `fun main() {
+    val n = readLine()!!.trim().toInt()
+    println(n * 2)
+}`,
+			input:    []string{"5"},
+			expected: "10\n",
+		},
+		{
+			language: "pypy3",
+			code:     `print(int(input()) * 2)`,
+			input:    []string{"5"},
+			expected: "10\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -302,7 +404,7 @@ if data:
 				MaxFilesSize:  100 * 1024 * 1024,
 				MaxOutputSize: 1024 * 1024,
 			}
-			res, err := sbRunner.Run(req)
+			res, err := sbRunner.Run(context.Background(), req)
 			if err != nil {
 				t.Fatalf("Run failed: %v", err)
 			}
@@ -348,6 +450,26 @@ func TestSandboxRunner_BuildFailure(t *testing.T) {
 			language: "python3",
 			code:     "invalid Python code",
 		},
+		{
+			language: "rust",
+			code:     "invalid Rust code",
+		},
+		{
+			language: "csharp",
+			code:     "invalid C# code",
+		},
+		{
+			language: "perl",
+			code:     "sub invalid {",
+		},
+		{
+			language: "kotlin",
+			code:     "invalid Kotlin code",
+		},
+		{
+			language: "pypy3",
+			code:     "invalid Python code",
+		},
 	}
 
 	for _, tt := range tests {
@@ -361,16 +483,269 @@ func TestSandboxRunner_BuildFailure(t *testing.T) {
 				MaxFilesSize:  100 * 1024 * 1024,
 				MaxOutputSize: 1024 * 1024,
 			}
-			res, err := sbRunner.Run(req)
+			res, err := sbRunner.Run(context.Background(), req)
 			if err != nil {
 				t.Fatalf("Run failed: %v", err)
 			}
-			hasBuildStep := tt.language == "c" || tt.language == "c++" || tt.language == "go" || tt.language == "java"
+			hasBuildStep := slices.Contains([]string{"c", "c++", "go", "java", "rust", "csharp", "perl", "kotlin"}, tt.language)
 			if hasBuildStep {
 				if res.Status != models.AttemptStatusBuildFailed {
 					t.Fatalf("Expected build failure, got status: %v", res.Status)
 				}
+				if !strings.HasPrefix(res.Error, "compiler ") || res.CompileOutput == "" {
+					t.Fatalf("Unexpected error %q, compile output %q", res.Error, res.CompileOutput)
+				}
 			}
 		})
 	}
 }
+
+func TestSandboxRunner_CompileOutput(t *testing.T) {
+	res, err := sbRunner.Run(context.Background(), &dto.RunRequest{
+		Image:         "perl",
+		Code:          `print "ok\n";`,
+		Input:         []string{""},
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected status: %v, error: %s", res.Status, res.Error)
+	}
+	// perl -c reports the checked file
+	if res.CompileOutput != "main.pl syntax OK" {
+		t.Fatalf("Unexpected compile output: %q", res.CompileOutput)
+	}
+}
+
+func TestSandboxRunner_CompileWarnings(t *testing.T) {
+	res, err := sbRunner.Run(context.Background(), &dto.RunRequest{
+		Image:         "c++",
+		Code:          "int main() { int unused = 1; return 0; }",
+		Input:         []string{""},
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected status: %v, error: %s", res.Status, res.Error)
+	}
+	if !strings.Contains(res.CompileOutput, "main.cpp") || !strings.Contains(res.CompileOutput, "warning") {
+		t.Fatalf("Warnings of a successful build are not returned: %q", res.CompileOutput)
+	}
+}
+
+func TestSandboxRunner_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	req := &dto.RunRequest{
+		Image:         "python3",
+		Code:          "while True:\n    pass",
+		Input:         []string{""},
+		Timeout:       10000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	}
+	start := time.Now()
+	_, err := sbRunner.Run(ctx, req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Process was not killed on cancel, run took %s", elapsed)
+	}
+}
+
+func TestSandboxRunner_Checker(t *testing.T) {
+	checker := `import sys
+out = open(sys.argv[2]).read().split()
+ans = open(sys.argv[3]).read().split()
+if out != ans:
+    print("expected", ans, "found", out, file=sys.stderr)
+    sys.exit(1)
+`
+	req := &dto.RunRequest{
+		Image:         "python3",
+		Code:          "a, b = map(int, input().split())\nprint(a + b)",
+		Input:         []string{"1 2\n", "2 2\n"},
+		Expected:      []string{"3", "5"},
+		Checker:       &dto.Checker{Image: "python3", Code: checker},
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	}
+	res, err := sbRunner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful || len(res.Output) != 2 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if res.Output[0].Matched == nil || !*res.Output[0].Matched {
+		t.Errorf("First test must be accepted: %+v", res.Output[0])
+	}
+	if res.Output[1].Matched == nil || *res.Output[1].Matched || res.Output[1].CheckerComment == "" {
+		t.Errorf("Second test must be rejected with comment: %+v", res.Output[1])
+	}
+}
+
+func TestSandboxRunner_MultipleFiles(t *testing.T) {
+	tests := []struct {
+		language string
+		files    []dto.File
+		expected string
+	}{
+		{
+			language: "python3",
+			files: []dto.File{
+				{Path: "solution.py", Content: "from pkg.util import add\nprint(add(2, 3))"},
+				{Path: "pkg/__init__.py"},
+				{Path: "pkg/util.py", Content: "def add(a, b):\n    return a + b"},
+			},
+			expected: "5\n",
+		},
+		{
+			language: "c++",
+			files: []dto.File{
+				{Path: "main.cpp", Content: "#include <iostream>\n#include \"add.h\"\nint main() { std::cout << add(2, 3) << std::endl; }"},
+				{Path: "add.h", Content: "int add(int a, int b);"},
+				{Path: "add.cpp", Content: "int add(int a, int b) { return a + b; }"},
+			},
+			expected: "5\n",
+		},
+		{
+			language: "java",
+			files: []dto.File{
+				{Path: "Main.java", Content: "public class Main { public static void main(String[] args) { System.out.println(Adder.add(2, 3)); } }"},
+				{Path: "Adder.java", Content: "public class Adder { static int add(int a, int b) { return a + b; } }"},
+			},
+			expected: "5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			req := &dto.RunRequest{
+				Image:         tt.language,
+				Files:         tt.files,
+				Input:         []string{""},
+				Timeout:       5000 * time.Millisecond,
+				MemoryLimit:   256 * 1024 * 1024,
+				MaxFilesSize:  100 * 1024 * 1024,
+				MaxOutputSize: 1024 * 1024,
+			}
+			res, err := sbRunner.Run(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if res.Status != models.AttemptStatusSuccessful {
+				t.Fatalf("Unexpected status: %v, error: %s", res.Status, res.Error)
+			}
+			if res.Output[0].Output != tt.expected {
+				t.Fatalf("Output mismatch: expected %q, got %q", tt.expected, res.Output[0].Output)
+			}
+		})
+	}
+
+	for _, path := range []string{"../x", "/etc/x", "a/../../x", ""} {
+		req := &dto.RunRequest{Image: "python3", Files: []dto.File{{Path: path}}, Timeout: time.Second}
+		if _, err := sbRunner.Run(context.Background(), req); !errors.Is(err, runner.ErrInvalidRequest) {
+			t.Errorf("Path %q must be rejected, got %v", path, err)
+		}
+	}
+}
+
+func TestSandboxRunner_Attachments(t *testing.T) {
+	req := &dto.RunRequest{
+		Image: "c++",
+		Code:  "#include <iostream>\n#include \"grader.h\"\nint solve(int a, int b) { return a + b; }",
+		Attachments: []dto.File{
+			{Path: "grader.h", Content: "int solve(int a, int b);"},
+			{Path: "grader.cpp", Content: "#include <iostream>\n#include <fstream>\n#include \"grader.h\"\n" +
+				"int main() { std::ofstream f(\"grader.h\"); std::cout << solve(2, 3) << ' ' << f.is_open() << std::endl; }"},
+		},
+		Input:         []string{""},
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	}
+	res, err := sbRunner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected status: %v, error: %s", res.Status, res.Error)
+	}
+	// The attachment can't be opened for writing
+	if res.Output[0].Output != "5 0\n" {
+		t.Fatalf("Output mismatch: expected %q, got %q", "5 0\n", res.Output[0].Output)
+	}
+
+	req.Files = []dto.File{{Path: "grader.h", Content: "int solve(int a, int b);"}}
+	if _, err := sbRunner.Run(context.Background(), req); !errors.Is(err, runner.ErrInvalidRequest) {
+		t.Errorf("Replacing an attachment must be rejected, got %v", err)
+	}
+}
+
+func TestSandboxRunner_AttachmentsNotRemoved(t *testing.T) {
+	req := &dto.RunRequest{
+		Image:         "sh",
+		Code:          "rm -f grader.h 2>/dev/null\nmv grader.h moved.h 2>/dev/null\ncat grader.h",
+		Attachments:   []dto.File{{Path: "grader.h", Content: "int solve(int a, int b);"}},
+		Input:         []string{""},
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	}
+	res, err := sbRunner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful || len(res.Output) != 1 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if res.Output[0].Output != "int solve(int a, int b);" {
+		t.Fatalf("Attachment must not be removed, got %q", res.Output[0].Output)
+	}
+}
+
+func TestSandboxRunner_FileIO(t *testing.T) {
+	req := &dto.RunRequest{
+		Image:         "python3",
+		Code:          "a, b = map(int, open('input.txt').read().split())\nopen('output.txt', 'w').write(str(a + b))\nprint('ignored')",
+		Input:         []string{"1 2", "3 4"},
+		InputFile:     "input.txt",
+		OutputFile:    "output.txt",
+		Timeout:       5000 * time.Millisecond,
+		MemoryLimit:   256 * 1024 * 1024,
+		MaxFilesSize:  100 * 1024 * 1024,
+		MaxOutputSize: 1024 * 1024,
+	}
+	res, err := sbRunner.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if res.Status != models.AttemptStatusSuccessful || len(res.Output) != 2 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if res.Output[0].Output != "3" || res.Output[1].Output != "7" {
+		t.Fatalf("Unexpected outputs: %q, %q", res.Output[0].Output, res.Output[1].Output)
+	}
+
+	req.Files = []dto.File{{Path: "input.txt"}}
+	if _, err := sbRunner.Run(context.Background(), req); !errors.Is(err, runner.ErrInvalidRequest) {
+		t.Errorf("I/O file conflicting with a source file must be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package sandbox
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const warmupTimeout = 10 * time.Minute
+
+// warmup runs the warmup of one host directory once
+type warmup struct {
+	once sync.Once
+	// closed when the warmup is finished, even if it failed
+	done chan struct{}
+}
+
+func (w *warmup) run(c mountConfig) {
+	w.once.Do(func() {
+		warmCache(c)
+		close(w.done)
+	})
+}
+
+func (r *SandboxRunner) getWarmup(source string) (*warmup, bool) {
+	w, loaded := r.warmed.LoadOrStore(source, &warmup{done: make(chan struct{})})
+	return w.(*warmup), loaded
+}
+
+// warmCaches runs warmup commands of caches of the languages once per host directory
+// and waits for them. A failed warmup is logged, attempts still work with a cold cache.
+func (r *SandboxRunner) warmCaches(langs ...*languageConfig) {
+	for _, c := range warmupCaches(langs) {
+		w, _ := r.getWarmup(c.Source)
+		w.run(c)
+	}
+}
+
+// warmCachesAsync starts warmup of caches that weren't warmed yet and returns at
+// once. Attempts of the languages wait for it in waitWarmup.
+func (r *SandboxRunner) warmCachesAsync(langs ...*languageConfig) {
+	for _, c := range warmupCaches(langs) {
+		if w, started := r.getWarmup(c.Source); !started {
+			go w.run(c)
+		}
+	}
+}
+
+// waitWarmup waits until caches of the languages are warmed. Containers mount caches
+// as overlay lower directories, which must not change while they are mounted.
+func (r *SandboxRunner) waitWarmup(ctx context.Context, langs ...*languageConfig) error {
+	for _, c := range warmupCaches(langs) {
+		w, ok := r.warmed.Load(c.Source)
+		if !ok {
+			continue
+		}
+		select {
+		case <-w.(*warmup).done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func warmupCaches(langs []*languageConfig) []mountConfig {
+	var caches []mountConfig
+	for _, lang := range langs {
+		for _, c := range lang.Caches {
+			if len(c.Warmup) > 0 {
+				caches = append(caches, c)
+			}
+		}
+	}
+	return caches
+}
+
+func warmCache(c mountConfig) {
+	if err := os.MkdirAll(c.Source, 0777); err != nil {
+		slog.Error("failed to create cache directory", "path", c.Source, "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout)
+	defer cancel()
+
+	slog.Info("warming cache", "path", c.Source, "command", c.Warmup)
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, c.Warmup[0], c.Warmup[1:]...)
+	cmd.Dir = c.Source
+	cmd.Env = os.Environ()
+	for key, value := range c.WarmupEnv {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		slog.Error("failed to warm cache", "path", c.Source, "error", err, "output", string(out))
+	} else {
+		slog.Info("cache warmed", "path", c.Source, "time", time.Since(start))
+	}
+	// Sandboxed users read the cache through the overlay
+	if err := exec.Command("chmod", "-R", "777", c.Source).Run(); err != nil {
+		slog.Error("failed to chmod cache", "path", c.Source, "error", err)
+	}
+}
@@ -0,0 +1,78 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSandboxRunner_WarmCaches(t *testing.T) {
+	dir := t.TempDir()
+	lang := &languageConfig{Caches: []mountConfig{{
+		Source:    dir,
+		Target:    "/cache",
+		Warmup:    []string{"/bin/sh", "-c", `echo -n x >> "$FILE"`},
+		WarmupEnv: map[string]string{"FILE": "warm"},
+	}}}
+	r := &SandboxRunner{}
+	r.warmCaches(lang, lang)
+	r.warmCaches(lang)
+	data, err := os.ReadFile(filepath.Join(dir, "warm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "x" {
+		t.Fatalf("Warmup must run once, got %q", data)
+	}
+}
+
+func TestSandboxRunner_WarmCachesAsync(t *testing.T) {
+	dir := t.TempDir()
+	lang := &languageConfig{Caches: []mountConfig{{
+		Source: dir,
+		Target: "/cache",
+		Warmup: []string{"/bin/sh", "-c", "sleep 0.2 && echo -n x >> warm"},
+	}}}
+	r := &SandboxRunner{}
+	start := time.Now()
+	r.warmCachesAsync(lang)
+	r.warmCachesAsync(lang)
+	if time.Since(start) > 100*time.Millisecond {
+		t.Fatalf("Warmup must not block, took %s", time.Since(start))
+	}
+	// Waits for the running warmup instead of starting another one
+	r.warmCaches(lang)
+	data, err := os.ReadFile(filepath.Join(dir, "warm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "x" {
+		t.Fatalf("Warmup must run once, got %q", data)
+	}
+}
+
+func TestSandboxRunner_WaitWarmup(t *testing.T) {
+	dir := t.TempDir()
+	lang := &languageConfig{Caches: []mountConfig{{
+		Source: dir,
+		Target: "/cache",
+		Warmup: []string{"/bin/sh", "-c", "sleep 0.2 && echo -n x >> warm"},
+	}}}
+	r := &SandboxRunner{}
+	r.warmCachesAsync(lang)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.waitWarmup(ctx, lang); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait must stop with the context, got %v", err)
+	}
+	if err := r.waitWarmup(context.Background(), lang); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "warm")); err != nil {
+		t.Fatalf("Wait returned before the warmup finished: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package testutil has a fake runner and file storage shared by tests of the judge
+// and the transports.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+// EchoRunner returns inputs of the attempt as test outputs. Every test takes Delay
+// and reports a progress event. Code "panic" makes it panic.
+type EchoRunner struct {
+	Delay time.Duration
+}
+
+func (r *EchoRunner) Run(ctx context.Context, req *dto.RunRequest) (*dto.RunResult, error) {
+	if req.Code == "panic" {
+		panic("runner panic")
+	}
+	result := &dto.RunResult{Status: models.AttemptStatusSuccessful}
+	for i, input := range req.Input {
+		if req.OnEvent != nil {
+			req.OnEvent(dto.RunEvent{Type: models.AttemptEventTestStarted, Test: i})
+		}
+		select {
+		case <-time.After(r.Delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		result.Output = append(result.Output, dto.RunCaseResult{Output: input})
+	}
+	return result, nil
+}
+
+// Storage serves files by name from memory
+type Storage map[string]string
+
+func (s Storage) GetFile(ctx context.Context, filename string) (io.Reader, error) {
+	data, ok := s[filename]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", filename)
+	}
+	return strings.NewReader(data), nil
+}
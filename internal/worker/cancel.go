@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// How long a cancel request for an attempt that is not running yet is kept.
+// The attempt may still be waiting in the broker queue when it is cancelled.
+const cancelTTL = 5 * time.Minute
+
+// cancellations tracks contexts of running attempts so they can be cancelled by id
+type cancellations struct {
+	mu      sync.Mutex
+	running map[int64]context.CancelFunc
+	pending map[int64]time.Time
+}
+
+func newCancellations() *cancellations {
+	return &cancellations{
+		running: make(map[int64]context.CancelFunc),
+		pending: make(map[int64]time.Time),
+	}
+}
+
+// start returns context for the attempt and function that must be called when the attempt
+// is finished. Context is already cancelled if the attempt was cancelled before start
+func (c *cancellations) start(id int64) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if at, ok := c.pending[id]; ok {
+		delete(c.pending, id)
+		if time.Since(at) < cancelTTL {
+			cancel()
+		}
+	}
+	c.running[id] = cancel
+	return ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.running, id)
+		cancel()
+	}
+}
+
+func (c *cancellations) cancel(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.running[id]; ok {
+		cancel()
+		return
+	}
+	now := time.Now()
+	for pendingId, at := range c.pending {
+		if now.Sub(at) >= cancelTTL {
+			delete(c.pending, pendingId)
+		}
+	}
+	c.pending[id] = now
+}
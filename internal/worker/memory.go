@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+// MemorySource is an in-memory job source and result sink, used in tests and
+// when the runner is embedded into another program.
+type MemorySource struct {
+	jobs      chan *models.AttemptRequest
+	results   chan *models.AttemptResponse
+	events    chan *models.AttemptEvent
+	cancelled chan int64
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewMemorySource creates a source, buffer is the capacity of every channel.
+// Events are dropped if nobody reads them and the buffer is full.
+func NewMemorySource(buffer int) *MemorySource {
+	return &MemorySource{
+		jobs:      make(chan *models.AttemptRequest, buffer),
+		results:   make(chan *models.AttemptResponse, buffer),
+		events:    make(chan *models.AttemptEvent, buffer),
+		cancelled: make(chan int64, buffer),
+		stop:      make(chan struct{}),
+	}
+}
+
+func (m *MemorySource) Submit(req *models.AttemptRequest) {
+	m.jobs <- req
+}
+
+func (m *MemorySource) Cancel(id int64) {
+	m.cancelled <- id
+}
+
+func (m *MemorySource) Results() <-chan *models.AttemptResponse {
+	return m.results
+}
+
+func (m *MemorySource) Events() <-chan *models.AttemptEvent {
+	return m.events
+}
+
+func (m *MemorySource) Next() (*Job, bool) {
+	select {
+	case <-m.stop:
+		return nil, false
+	default:
+	}
+	select {
+	case req := <-m.jobs:
+		return &Job{Request: req}, true
+	case <-m.stop:
+		return nil, false
+	}
+}
+
+func (m *MemorySource) Ack(job *Job) {}
+
+func (m *MemorySource) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+func (m *MemorySource) Cancelled() <-chan int64 {
+	return m.cancelled
+}
+
+func (m *MemorySource) SendEvent(job *Job, event *models.AttemptEvent) {
+	select {
+	case m.events <- event:
+	default:
+	}
+}
+
+func (m *MemorySource) SendResult(job *Job, resp *models.AttemptResponse) {
+	m.results <- resp
+}
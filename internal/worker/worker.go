@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/mappers"
+	"github.com/cutekitek/rankode-runner/internal/repository/dto"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+)
+
+type Job struct {
+	Request *models.AttemptRequest
+	// Transport specific data, e.g. the message to acknowledge
+	Data any
+}
+
+// JobSource delivers attempts to the worker pool
+type JobSource interface {
+	// Next blocks until a job is available. Returns false after Stop
+	Next() (*Job, bool)
+	// Ack is called after the job result was sent
+	Ack(job *Job)
+	// Stop stops delivering jobs, running jobs are still finished
+	Stop()
+}
+
+// ResultSink publishes attempt results and progress events
+type ResultSink interface {
+	SendEvent(job *Job, event *models.AttemptEvent)
+	SendResult(job *Job, resp *models.AttemptResponse)
+}
+
+// CancelSource is implemented by sources that receive attempt cancel requests
+type CancelSource interface {
+	// Ids of attempts to cancel
+	Cancelled() <-chan int64
+}
+
+// Pool runs jobs from a source with the judge and sends results to a sink
+type Pool struct {
+	workers       int
+	judge         *judge.Judge
+	source        JobSource
+	sink          ResultSink
+	cancellations *cancellations
+	wg            *sync.WaitGroup
+	done          chan struct{}
+}
+
+func NewPool(workers int, judge *judge.Judge, source JobSource, sink ResultSink) *Pool {
+	return &Pool{
+		workers:       workers,
+		judge:         judge,
+		source:        source,
+		sink:          sink,
+		cancellations: newCancellations(),
+		wg:            &sync.WaitGroup{},
+		done:          make(chan struct{}),
+	}
+}
+
+func (p *Pool) Start() {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	if cs, ok := p.source.(CancelSource); ok {
+		go func() {
+			for {
+				select {
+				case id := <-cs.Cancelled():
+					slog.Debug("cancel attempt", "id", id)
+					p.cancellations.cancel(id)
+				case <-p.done:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Close stops the source and waits for running jobs to finish
+func (p *Pool) Close() {
+	p.source.Stop()
+	p.wg.Wait()
+	close(p.done)
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for {
+		job, ok := p.source.Next()
+		if !ok {
+			break
+		}
+		p.process(job)
+	}
+	slog.Info("end worker")
+}
+
+func (p *Pool) process(job *Job) {
+	ctx, release := p.cancellations.start(job.Request.Id)
+	defer release()
+	resp := p.judge.Process(ctx, job.Request, func(event dto.RunEvent) {
+		p.sink.SendEvent(job, mappers.RunEventToAttemptEvent(job.Request, event))
+	})
+	p.sink.SendResult(job, resp)
+	p.source.Ack(job)
+}
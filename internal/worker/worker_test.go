@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cutekitek/rankode-runner/internal/judge"
+	"github.com/cutekitek/rankode-runner/internal/repository/models"
+	"github.com/cutekitek/rankode-runner/internal/testutil"
+)
+
+func newTestPool(delay time.Duration) (*Pool, *MemorySource) {
+	storage := testutil.Storage{"in1": "1", "in2": "2"}
+	source := NewMemorySource(16)
+	pool := NewPool(2, judge.NewJudge(judge.Config{}, &testutil.EchoRunner{Delay: delay}, storage, nil), source, source)
+	pool.Start()
+	return pool, source
+}
+
+func waitResult(t *testing.T, source *MemorySource) *models.AttemptResponse {
+	t.Helper()
+	select {
+	case resp := <-source.Results():
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result received")
+	}
+	return nil
+}
+
+func TestPool_Results(t *testing.T) {
+	pool, source := newTestPool(0)
+	defer pool.Close()
+
+	source.Submit(&models.AttemptRequest{Id: 1, TestCases: []models.TestCase{{Id: 10, InputFileName: "in1"}, {Id: 11, InputFileName: "in2"}}})
+	resp := waitResult(t, source)
+	if resp.Id != 1 || resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	if len(resp.Tests) != 2 || resp.Tests[1].CaseId != 11 || resp.Tests[1].Output != "2" {
+		t.Fatalf("Unexpected tests: %+v", resp.Tests)
+	}
+}
+
+func TestPool_FailuresKeepWorkers(t *testing.T) {
+	pool, source := newTestPool(0)
+	defer pool.Close()
+
+	// More failing attempts than workers, every one must be answered
+	for i := int64(1); i <= 4; i++ {
+		source.Submit(&models.AttemptRequest{Id: i, TestCases: []models.TestCase{{InputFileName: "missing"}}})
+	}
+	source.Submit(&models.AttemptRequest{Id: 5, Code: "panic"})
+	source.Submit(&models.AttemptRequest{Id: 6, Code: "panic"})
+	for i := 0; i < 6; i++ {
+		if resp := waitResult(t, source); resp.Status != models.AttemptStatusInternalError {
+			t.Fatalf("Expected internal error, got: %+v", resp)
+		}
+	}
+
+	source.Submit(&models.AttemptRequest{Id: 7, TestCases: []models.TestCase{{InputFileName: "in1"}}})
+	if resp := waitResult(t, source); resp.Id != 7 || resp.Status != models.AttemptStatusSuccessful {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestPool_Cancel(t *testing.T) {
+	pool, source := newTestPool(time.Minute)
+	defer pool.Close()
+
+	// Cancel request may come before or after the worker took the attempt
+	source.Submit(&models.AttemptRequest{Id: 1, TestCases: []models.TestCase{{InputFileName: "in1"}}})
+	source.Cancel(1)
+	if resp := waitResult(t, source); resp.Status != models.AttemptStatusCancelled {
+		t.Fatalf("Expected cancelled status, got: %+v", resp)
+	}
+}
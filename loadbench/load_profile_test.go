@@ -1,6 +1,7 @@
 package loadbench
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -79,7 +80,7 @@ func runLoadScenario(t *testing.T, scenario loadScenario) {
 		MaxOutputSize: 1024 * 1024,
 	}
 
-	if res, err := runner.Run(req); err != nil || res.Status != models.AttemptStatusSuccessful {
+	if res, err := runner.Run(context.Background(), req); err != nil || res.Status != models.AttemptStatusSuccessful {
 		if err != nil {
 			t.Fatalf("warmup failed: %v", err)
 		}
@@ -98,7 +99,7 @@ func runLoadScenario(t *testing.T, scenario loadScenario) {
 			defer wg.Done()
 			for range jobs {
 				requestStart := time.Now()
-				res, err := runner.Run(req)
+				res, err := runner.Run(context.Background(), req)
 				durations <- time.Since(requestStart)
 				if err != nil || res.Status != models.AttemptStatusSuccessful {
 					failures.Add(1)
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -49,7 +50,7 @@ func failOnError(err error, msg string) {
 }
 
 // publisher - горутина, которая отправляет сообщения в очередь
-func publisher(wg *sync.WaitGroup, ch *amqp091.Channel, done <-chan struct{}, body []byte) {
+func publisher(wg *sync.WaitGroup, ch *amqp091.Channel, done <-chan struct{}, body []byte, replyTo string, id int) {
 	defer wg.Done()
 
 	for i := 0; i < 1000; i++ {
@@ -66,8 +67,10 @@ func publisher(wg *sync.WaitGroup, ch *amqp091.Channel, done <-chan struct{}, bo
 				false,                // mandatory
 				false,                // immediate
 				amqp091.Publishing{
-					ContentType: "application/json",
-					Body:        body,
+					ContentType:   "application/json",
+					ReplyTo:       replyTo, // ответы придут в нашу собственную очередь
+					CorrelationId: strconv.Itoa(id) + "-" + strconv.Itoa(i),
+					Body:          body,
 				})
 			if err != nil {
 				// Логируем ошибку, но не останавливаем поток
@@ -78,16 +81,16 @@ func publisher(wg *sync.WaitGroup, ch *amqp091.Channel, done <-chan struct{}, bo
 }
 
 // consumerAndReporter - горутина, которая слушает ответы и выводит статистику
-func consumerAndReporter(ch *amqp091.Channel, done <-chan struct{}) {
+func consumerAndReporter(ch *amqp091.Channel, done <-chan struct{}, replyQueue string) {
 	// Начинаем слушать очередь ответов
 	msgs, err := ch.Consume(
-		responseQueueName, // queue
-		"",                // consumer
-		true,              // auto-ack (для простоты; в реальных системах лучше false)
-		false,             // exclusive
-		false,             // no-local
-		false,             // no-wait
-		nil,               // args
+		replyQueue, // queue
+		"",         // consumer
+		true,       // auto-ack (для простоты; в реальных системах лучше false)
+		false,      // exclusive
+		false,      // no-local
+		false,      // no-wait
+		nil,        // args
 	)
 	failOnError(err, "Failed to register a consumer")
 
@@ -143,6 +146,17 @@ func main() {
 	)
 	failOnError(err, "Failed to declare response queue")
 
+	// Эксклюзивная очередь с именем от сервера, чтобы получать только свои ответы
+	replyQueue, err := ch.QueueDeclare(
+		"",    // name
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	failOnError(err, "Failed to declare reply queue")
+
 	// --- Подготовка данных для отправки ---
 	task := TaskRequest{
 		Language:      "python3",
@@ -166,12 +180,12 @@ func main() {
 	// Запуск потоков-публикаторов
 	for i := 0; i < publisherCount; i++ {
 		wg.Add(1)
-		go publisher(&wg, ch, done, body)
+		go publisher(&wg, ch, done, body, replyQueue.Name, i)
 	}
 	log.Printf("Started %d publishers...\n", publisherCount)
 
 	// Запуск потока-слушателя
-	go consumerAndReporter(ch, done)
+	go consumerAndReporter(ch, done, replyQueue.Name)
 
 	// --- Ожидание сигнала о завершении (Ctrl+C) ---
 	log.Println("Program is running. Press CTRL+C to exit.")
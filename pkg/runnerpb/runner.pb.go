@@ -0,0 +1,1343 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: runner.proto
+
+package runnerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AttemptStatus int32
+
+const (
+	AttemptStatus_ATTEMPT_STATUS_SUCCESSFUL     AttemptStatus = 0
+	AttemptStatus_ATTEMPT_STATUS_BUILD_FAILED   AttemptStatus = 1
+	AttemptStatus_ATTEMPT_STATUS_RUN_FAILED     AttemptStatus = 2
+	AttemptStatus_ATTEMPT_STATUS_INTERNAL_ERROR AttemptStatus = 3
+	AttemptStatus_ATTEMPT_STATUS_CREATED        AttemptStatus = 4
+	AttemptStatus_ATTEMPT_STATUS_CANCELLED      AttemptStatus = 5
+)
+
+// Enum value maps for AttemptStatus.
+var (
+	AttemptStatus_name = map[int32]string{
+		0: "ATTEMPT_STATUS_SUCCESSFUL",
+		1: "ATTEMPT_STATUS_BUILD_FAILED",
+		2: "ATTEMPT_STATUS_RUN_FAILED",
+		3: "ATTEMPT_STATUS_INTERNAL_ERROR",
+		4: "ATTEMPT_STATUS_CREATED",
+		5: "ATTEMPT_STATUS_CANCELLED",
+	}
+	AttemptStatus_value = map[string]int32{
+		"ATTEMPT_STATUS_SUCCESSFUL":     0,
+		"ATTEMPT_STATUS_BUILD_FAILED":   1,
+		"ATTEMPT_STATUS_RUN_FAILED":     2,
+		"ATTEMPT_STATUS_INTERNAL_ERROR": 3,
+		"ATTEMPT_STATUS_CREATED":        4,
+		"ATTEMPT_STATUS_CANCELLED":      5,
+	}
+)
+
+func (x AttemptStatus) Enum() *AttemptStatus {
+	p := new(AttemptStatus)
+	*p = x
+	return p
+}
+
+func (x AttemptStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttemptStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_runner_proto_enumTypes[0].Descriptor()
+}
+
+func (AttemptStatus) Type() protoreflect.EnumType {
+	return &file_runner_proto_enumTypes[0]
+}
+
+func (x AttemptStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttemptStatus.Descriptor instead.
+func (AttemptStatus) EnumDescriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{0}
+}
+
+type TestCaseStatus int32
+
+const (
+	TestCaseStatus_TEST_CASE_STATUS_COMPLETE          TestCaseStatus = 0
+	TestCaseStatus_TEST_CASE_STATUS_COMPILATION_ERROR TestCaseStatus = 1
+	TestCaseStatus_TEST_CASE_STATUS_RUNNING_ERROR     TestCaseStatus = 2
+	TestCaseStatus_TEST_CASE_STATUS_OUT_OF_MEMORY     TestCaseStatus = 3
+	TestCaseStatus_TEST_CASE_STATUS_TIMEOUT           TestCaseStatus = 4
+	TestCaseStatus_TEST_CASE_STATUS_OUTPUT_OVERFLOW   TestCaseStatus = 5
+)
+
+// Enum value maps for TestCaseStatus.
+var (
+	TestCaseStatus_name = map[int32]string{
+		0: "TEST_CASE_STATUS_COMPLETE",
+		1: "TEST_CASE_STATUS_COMPILATION_ERROR",
+		2: "TEST_CASE_STATUS_RUNNING_ERROR",
+		3: "TEST_CASE_STATUS_OUT_OF_MEMORY",
+		4: "TEST_CASE_STATUS_TIMEOUT",
+		5: "TEST_CASE_STATUS_OUTPUT_OVERFLOW",
+	}
+	TestCaseStatus_value = map[string]int32{
+		"TEST_CASE_STATUS_COMPLETE":          0,
+		"TEST_CASE_STATUS_COMPILATION_ERROR": 1,
+		"TEST_CASE_STATUS_RUNNING_ERROR":     2,
+		"TEST_CASE_STATUS_OUT_OF_MEMORY":     3,
+		"TEST_CASE_STATUS_TIMEOUT":           4,
+		"TEST_CASE_STATUS_OUTPUT_OVERFLOW":   5,
+	}
+)
+
+func (x TestCaseStatus) Enum() *TestCaseStatus {
+	p := new(TestCaseStatus)
+	*p = x
+	return p
+}
+
+func (x TestCaseStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TestCaseStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_runner_proto_enumTypes[1].Descriptor()
+}
+
+func (TestCaseStatus) Type() protoreflect.EnumType {
+	return &file_runner_proto_enumTypes[1]
+}
+
+func (x TestCaseStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TestCaseStatus.Descriptor instead.
+func (TestCaseStatus) EnumDescriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{1}
+}
+
+type AttemptEventType int32
+
+const (
+	AttemptEventType_ATTEMPT_EVENT_TYPE_QUEUED        AttemptEventType = 0
+	AttemptEventType_ATTEMPT_EVENT_TYPE_COMPILING     AttemptEventType = 1
+	AttemptEventType_ATTEMPT_EVENT_TYPE_COMPILED      AttemptEventType = 2
+	AttemptEventType_ATTEMPT_EVENT_TYPE_TEST_STARTED  AttemptEventType = 3
+	AttemptEventType_ATTEMPT_EVENT_TYPE_TEST_FINISHED AttemptEventType = 4
+)
+
+// Enum value maps for AttemptEventType.
+var (
+	AttemptEventType_name = map[int32]string{
+		0: "ATTEMPT_EVENT_TYPE_QUEUED",
+		1: "ATTEMPT_EVENT_TYPE_COMPILING",
+		2: "ATTEMPT_EVENT_TYPE_COMPILED",
+		3: "ATTEMPT_EVENT_TYPE_TEST_STARTED",
+		4: "ATTEMPT_EVENT_TYPE_TEST_FINISHED",
+	}
+	AttemptEventType_value = map[string]int32{
+		"ATTEMPT_EVENT_TYPE_QUEUED":        0,
+		"ATTEMPT_EVENT_TYPE_COMPILING":     1,
+		"ATTEMPT_EVENT_TYPE_COMPILED":      2,
+		"ATTEMPT_EVENT_TYPE_TEST_STARTED":  3,
+		"ATTEMPT_EVENT_TYPE_TEST_FINISHED": 4,
+	}
+)
+
+func (x AttemptEventType) Enum() *AttemptEventType {
+	p := new(AttemptEventType)
+	*p = x
+	return p
+}
+
+func (x AttemptEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AttemptEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_runner_proto_enumTypes[2].Descriptor()
+}
+
+func (AttemptEventType) Type() protoreflect.EnumType {
+	return &file_runner_proto_enumTypes[2]
+}
+
+func (x AttemptEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AttemptEventType.Descriptor instead.
+func (AttemptEventType) EnumDescriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{2}
+}
+
+type TestCase struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Order     int32                  `protobuf:"varint,2,opt,name=order,proto3" json:"order,omitempty"`
+	InputFile string                 `protobuf:"bytes,3,opt,name=input_file,json=inputFile,proto3" json:"input_file,omitempty"`
+	// Inline input used instead of input_file if set
+	Input *string `protobuf:"bytes,4,opt,name=input,proto3,oneof" json:"input,omitempty"`
+	// Optional expected output, compared ignoring trailing whitespace
+	Expected      *string `protobuf:"bytes,5,opt,name=expected,proto3,oneof" json:"expected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestCase) Reset() {
+	*x = TestCase{}
+	mi := &file_runner_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestCase) ProtoMessage() {}
+
+func (x *TestCase) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestCase.ProtoReflect.Descriptor instead.
+func (*TestCase) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TestCase) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TestCase) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *TestCase) GetInputFile() string {
+	if x != nil {
+		return x.InputFile
+	}
+	return ""
+}
+
+func (x *TestCase) GetInput() string {
+	if x != nil && x.Input != nil {
+		return *x.Input
+	}
+	return ""
+}
+
+func (x *TestCase) GetExpected() string {
+	if x != nil && x.Expected != nil {
+		return *x.Expected
+	}
+	return ""
+}
+
+type SourceFile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slash separated path relative to the working directory
+	Path          string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Content       string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceFile) Reset() {
+	*x = SourceFile{}
+	mi := &file_runner_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceFile) ProtoMessage() {}
+
+func (x *SourceFile) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceFile.ProtoReflect.Descriptor instead.
+func (*SourceFile) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SourceFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SourceFile) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type Attachment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slash separated path relative to the working directory
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Name of the file in file storage
+	File          string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_runner_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Attachment) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Attachment) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+type AttemptRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Assigned by the server if zero
+	Id          int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Language    string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Code        string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	MemoryLimit int64  `protobuf:"varint,4,opt,name=memory_limit,json=memoryLimit,proto3" json:"memory_limit,omitempty"`
+	// Milliseconds per test
+	Timeout          int64       `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	MaxOutputSize    int64       `protobuf:"varint,6,opt,name=max_output_size,json=maxOutputSize,proto3" json:"max_output_size,omitempty"`
+	Priority         uint32      `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	TestCases        []*TestCase `protobuf:"bytes,8,rep,name=test_cases,json=testCases,proto3" json:"test_cases,omitempty"`
+	VerificationFile string      `protobuf:"bytes,9,opt,name=verification_file,json=verificationFile,proto3" json:"verification_file,omitempty"`
+	// Problem package in file storage, test cases without input are taken from it
+	Package        string `protobuf:"bytes,10,opt,name=package,proto3" json:"package,omitempty"`
+	PackageVersion string `protobuf:"bytes,11,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`
+	// Additional source files of multi-file submissions
+	Files []*SourceFile `protobuf:"bytes,12,rep,name=files,proto3" json:"files,omitempty"`
+	// Zip archive with source files
+	Archive []byte `protobuf:"bytes,13,opt,name=archive,proto3" json:"archive,omitempty"`
+	// Problem files placed read-only next to the sources before build
+	Attachments []*Attachment `protobuf:"bytes,14,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	// Files the solution reads tests from and writes outputs to instead of stdin and stdout
+	InputFile     string `protobuf:"bytes,15,opt,name=input_file,json=inputFile,proto3" json:"input_file,omitempty"`
+	OutputFile    string `protobuf:"bytes,16,opt,name=output_file,json=outputFile,proto3" json:"output_file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttemptRequest) Reset() {
+	*x = AttemptRequest{}
+	mi := &file_runner_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptRequest) ProtoMessage() {}
+
+func (x *AttemptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptRequest.ProtoReflect.Descriptor instead.
+func (*AttemptRequest) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AttemptRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttemptRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetMemoryLimit() int64 {
+	if x != nil {
+		return x.MemoryLimit
+	}
+	return 0
+}
+
+func (x *AttemptRequest) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *AttemptRequest) GetMaxOutputSize() int64 {
+	if x != nil {
+		return x.MaxOutputSize
+	}
+	return 0
+}
+
+func (x *AttemptRequest) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *AttemptRequest) GetTestCases() []*TestCase {
+	if x != nil {
+		return x.TestCases
+	}
+	return nil
+}
+
+func (x *AttemptRequest) GetVerificationFile() string {
+	if x != nil {
+		return x.VerificationFile
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetPackage() string {
+	if x != nil {
+		return x.Package
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetPackageVersion() string {
+	if x != nil {
+		return x.PackageVersion
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetFiles() []*SourceFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *AttemptRequest) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *AttemptRequest) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *AttemptRequest) GetInputFile() string {
+	if x != nil {
+		return x.InputFile
+	}
+	return ""
+}
+
+func (x *AttemptRequest) GetOutputFile() string {
+	if x != nil {
+		return x.OutputFile
+	}
+	return ""
+}
+
+type TestStatus struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TestId int64                  `protobuf:"varint,1,opt,name=test_id,json=testId,proto3" json:"test_id,omitempty"`
+	Status TestCaseStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=rankode.runner.v1.TestCaseStatus" json:"status,omitempty"`
+	Output string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`
+	// Milliseconds
+	ExecutionTime int64 `protobuf:"varint,4,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"`
+	// Set only for tests with expected output or checker
+	Matched        *bool  `protobuf:"varint,5,opt,name=matched,proto3,oneof" json:"matched,omitempty"`
+	CheckerComment string `protobuf:"bytes,6,opt,name=checker_comment,json=checkerComment,proto3" json:"checker_comment,omitempty"`
+	Group          string `protobuf:"bytes,7,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TestStatus) Reset() {
+	*x = TestStatus{}
+	mi := &file_runner_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestStatus) ProtoMessage() {}
+
+func (x *TestStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestStatus.ProtoReflect.Descriptor instead.
+func (*TestStatus) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TestStatus) GetTestId() int64 {
+	if x != nil {
+		return x.TestId
+	}
+	return 0
+}
+
+func (x *TestStatus) GetStatus() TestCaseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TestCaseStatus_TEST_CASE_STATUS_COMPLETE
+}
+
+func (x *TestStatus) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *TestStatus) GetExecutionTime() int64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+func (x *TestStatus) GetMatched() bool {
+	if x != nil && x.Matched != nil {
+		return *x.Matched
+	}
+	return false
+}
+
+func (x *TestStatus) GetCheckerComment() string {
+	if x != nil {
+		return x.CheckerComment
+	}
+	return ""
+}
+
+func (x *TestStatus) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+type AttemptResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status      AttemptStatus          `protobuf:"varint,2,opt,name=status,proto3,enum=rankode.runner.v1.AttemptStatus" json:"status,omitempty"`
+	Error       string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	MemoryUsage int64                  `protobuf:"varint,4,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	Tests       []*TestStatus          `protobuf:"bytes,5,rep,name=tests,proto3" json:"tests,omitempty"`
+	// Warnings and errors of the compiler, truncated to 64 KiB
+	CompileOutput string `protobuf:"bytes,6,opt,name=compile_output,json=compileOutput,proto3" json:"compile_output,omitempty"`
+	// Groups of the problem package with tests in the attempt
+	Groups        []*GroupResult `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttemptResponse) Reset() {
+	*x = AttemptResponse{}
+	mi := &file_runner_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptResponse) ProtoMessage() {}
+
+func (x *AttemptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptResponse.ProtoReflect.Descriptor instead.
+func (*AttemptResponse) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AttemptResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttemptResponse) GetStatus() AttemptStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AttemptStatus_ATTEMPT_STATUS_SUCCESSFUL
+}
+
+func (x *AttemptResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AttemptResponse) GetMemoryUsage() int64 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *AttemptResponse) GetTests() []*TestStatus {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+func (x *AttemptResponse) GetCompileOutput() string {
+	if x != nil {
+		return x.CompileOutput
+	}
+	return ""
+}
+
+func (x *AttemptResponse) GetGroups() []*GroupResult {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type GroupResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Points float64                `protobuf:"fixed64,2,opt,name=points,proto3" json:"points,omitempty"`
+	// Points of the group if it and all groups it depends on are passed, zero otherwise
+	Score float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	// All tests of the group are complete and matched
+	Passed        bool `protobuf:"varint,4,opt,name=passed,proto3" json:"passed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupResult) Reset() {
+	*x = GroupResult{}
+	mi := &file_runner_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupResult) ProtoMessage() {}
+
+func (x *GroupResult) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupResult.ProtoReflect.Descriptor instead.
+func (*GroupResult) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GroupResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GroupResult) GetPoints() float64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *GroupResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *GroupResult) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+type AttemptEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  AttemptEventType       `protobuf:"varint,2,opt,name=type,proto3,enum=rankode.runner.v1.AttemptEventType" json:"type,omitempty"`
+	// Test fields are meaningful only for test events
+	TestId        int64          `protobuf:"varint,3,opt,name=test_id,json=testId,proto3" json:"test_id,omitempty"`
+	Test          int32          `protobuf:"varint,4,opt,name=test,proto3" json:"test,omitempty"`
+	Status        TestCaseStatus `protobuf:"varint,5,opt,name=status,proto3,enum=rankode.runner.v1.TestCaseStatus" json:"status,omitempty"`
+	ExecutionTime int64          `protobuf:"varint,6,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttemptEvent) Reset() {
+	*x = AttemptEvent{}
+	mi := &file_runner_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptEvent) ProtoMessage() {}
+
+func (x *AttemptEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptEvent.ProtoReflect.Descriptor instead.
+func (*AttemptEvent) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AttemptEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttemptEvent) GetType() AttemptEventType {
+	if x != nil {
+		return x.Type
+	}
+	return AttemptEventType_ATTEMPT_EVENT_TYPE_QUEUED
+}
+
+func (x *AttemptEvent) GetTestId() int64 {
+	if x != nil {
+		return x.TestId
+	}
+	return 0
+}
+
+func (x *AttemptEvent) GetTest() int32 {
+	if x != nil {
+		return x.Test
+	}
+	return 0
+}
+
+func (x *AttemptEvent) GetStatus() TestCaseStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TestCaseStatus_TEST_CASE_STATUS_COMPLETE
+}
+
+func (x *AttemptEvent) GetExecutionTime() int64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+type RunUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Update:
+	//
+	//	*RunUpdate_Event
+	//	*RunUpdate_Result
+	Update        isRunUpdate_Update `protobuf_oneof:"update"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunUpdate) Reset() {
+	*x = RunUpdate{}
+	mi := &file_runner_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunUpdate) ProtoMessage() {}
+
+func (x *RunUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunUpdate.ProtoReflect.Descriptor instead.
+func (*RunUpdate) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RunUpdate) GetUpdate() isRunUpdate_Update {
+	if x != nil {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *RunUpdate) GetEvent() *AttemptEvent {
+	if x != nil {
+		if x, ok := x.Update.(*RunUpdate_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *RunUpdate) GetResult() *AttemptResponse {
+	if x != nil {
+		if x, ok := x.Update.(*RunUpdate_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isRunUpdate_Update interface {
+	isRunUpdate_Update()
+}
+
+type RunUpdate_Event struct {
+	Event *AttemptEvent `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type RunUpdate_Result struct {
+	Result *AttemptResponse `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*RunUpdate_Event) isRunUpdate_Update() {}
+
+func (*RunUpdate_Result) isRunUpdate_Update() {}
+
+type SubmitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitResponse) Reset() {
+	*x = SubmitResponse{}
+	mi := &file_runner_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitResponse) ProtoMessage() {}
+
+func (x *SubmitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitResponse.ProtoReflect.Descriptor instead.
+func (*SubmitResponse) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SubmitResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_runner_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CancelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelResponse) Reset() {
+	*x = CancelResponse{}
+	mi := &file_runner_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelResponse) ProtoMessage() {}
+
+func (x *CancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{11}
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_runner_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_runner_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_runner_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StreamEventsRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_runner_proto protoreflect.FileDescriptor
+
+const file_runner_proto_rawDesc = "" +
+	"\n" +
+	"\frunner.proto\x12\x11rankode.runner.v1\"\xa2\x01\n" +
+	"\bTestCase\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05order\x18\x02 \x01(\x05R\x05order\x12\x1d\n" +
+	"\n" +
+	"input_file\x18\x03 \x01(\tR\tinputFile\x12\x19\n" +
+	"\x05input\x18\x04 \x01(\tH\x00R\x05input\x88\x01\x01\x12\x1f\n" +
+	"\bexpected\x18\x05 \x01(\tH\x01R\bexpected\x88\x01\x01B\b\n" +
+	"\x06_inputB\v\n" +
+	"\t_expected\":\n" +
+	"\n" +
+	"SourceFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"4\n" +
+	"\n" +
+	"Attachment\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\"\xcd\x04\n" +
+	"\x0eAttemptRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12!\n" +
+	"\fmemory_limit\x18\x04 \x01(\x03R\vmemoryLimit\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x03R\atimeout\x12&\n" +
+	"\x0fmax_output_size\x18\x06 \x01(\x03R\rmaxOutputSize\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\rR\bpriority\x12:\n" +
+	"\n" +
+	"test_cases\x18\b \x03(\v2\x1b.rankode.runner.v1.TestCaseR\ttestCases\x12+\n" +
+	"\x11verification_file\x18\t \x01(\tR\x10verificationFile\x12\x18\n" +
+	"\apackage\x18\n" +
+	" \x01(\tR\apackage\x12'\n" +
+	"\x0fpackage_version\x18\v \x01(\tR\x0epackageVersion\x123\n" +
+	"\x05files\x18\f \x03(\v2\x1d.rankode.runner.v1.SourceFileR\x05files\x12\x18\n" +
+	"\aarchive\x18\r \x01(\fR\aarchive\x12?\n" +
+	"\vattachments\x18\x0e \x03(\v2\x1d.rankode.runner.v1.AttachmentR\vattachments\x12\x1d\n" +
+	"\n" +
+	"input_file\x18\x0f \x01(\tR\tinputFile\x12\x1f\n" +
+	"\voutput_file\x18\x10 \x01(\tR\n" +
+	"outputFile\"\x89\x02\n" +
+	"\n" +
+	"TestStatus\x12\x17\n" +
+	"\atest_id\x18\x01 \x01(\x03R\x06testId\x129\n" +
+	"\x06status\x18\x02 \x01(\x0e2!.rankode.runner.v1.TestCaseStatusR\x06status\x12\x16\n" +
+	"\x06output\x18\x03 \x01(\tR\x06output\x12%\n" +
+	"\x0eexecution_time\x18\x04 \x01(\x03R\rexecutionTime\x12\x1d\n" +
+	"\amatched\x18\x05 \x01(\bH\x00R\amatched\x88\x01\x01\x12'\n" +
+	"\x0fchecker_comment\x18\x06 \x01(\tR\x0echeckerComment\x12\x14\n" +
+	"\x05group\x18\a \x01(\tR\x05groupB\n" +
+	"\n" +
+	"\b_matched\"\xa8\x02\n" +
+	"\x0fAttemptResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x128\n" +
+	"\x06status\x18\x02 \x01(\x0e2 .rankode.runner.v1.AttemptStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12!\n" +
+	"\fmemory_usage\x18\x04 \x01(\x03R\vmemoryUsage\x123\n" +
+	"\x05tests\x18\x05 \x03(\v2\x1d.rankode.runner.v1.TestStatusR\x05tests\x12%\n" +
+	"\x0ecompile_output\x18\x06 \x01(\tR\rcompileOutput\x126\n" +
+	"\x06groups\x18\a \x03(\v2\x1e.rankode.runner.v1.GroupResultR\x06groups\"g\n" +
+	"\vGroupResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06points\x18\x02 \x01(\x01R\x06points\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12\x16\n" +
+	"\x06passed\x18\x04 \x01(\bR\x06passed\"\xe6\x01\n" +
+	"\fAttemptEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x127\n" +
+	"\x04type\x18\x02 \x01(\x0e2#.rankode.runner.v1.AttemptEventTypeR\x04type\x12\x17\n" +
+	"\atest_id\x18\x03 \x01(\x03R\x06testId\x12\x12\n" +
+	"\x04test\x18\x04 \x01(\x05R\x04test\x129\n" +
+	"\x06status\x18\x05 \x01(\x0e2!.rankode.runner.v1.TestCaseStatusR\x06status\x12%\n" +
+	"\x0eexecution_time\x18\x06 \x01(\x03R\rexecutionTime\"\x8c\x01\n" +
+	"\tRunUpdate\x127\n" +
+	"\x05event\x18\x01 \x01(\v2\x1f.rankode.runner.v1.AttemptEventH\x00R\x05event\x12<\n" +
+	"\x06result\x18\x02 \x01(\v2\".rankode.runner.v1.AttemptResponseH\x00R\x06resultB\b\n" +
+	"\x06update\" \n" +
+	"\x0eSubmitResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1f\n" +
+	"\rCancelRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x10\n" +
+	"\x0eCancelResponse\"%\n" +
+	"\x13StreamEventsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id*\xcb\x01\n" +
+	"\rAttemptStatus\x12\x1d\n" +
+	"\x19ATTEMPT_STATUS_SUCCESSFUL\x10\x00\x12\x1f\n" +
+	"\x1bATTEMPT_STATUS_BUILD_FAILED\x10\x01\x12\x1d\n" +
+	"\x19ATTEMPT_STATUS_RUN_FAILED\x10\x02\x12!\n" +
+	"\x1dATTEMPT_STATUS_INTERNAL_ERROR\x10\x03\x12\x1a\n" +
+	"\x16ATTEMPT_STATUS_CREATED\x10\x04\x12\x1c\n" +
+	"\x18ATTEMPT_STATUS_CANCELLED\x10\x05*\xe3\x01\n" +
+	"\x0eTestCaseStatus\x12\x1d\n" +
+	"\x19TEST_CASE_STATUS_COMPLETE\x10\x00\x12&\n" +
+	"\"TEST_CASE_STATUS_COMPILATION_ERROR\x10\x01\x12\"\n" +
+	"\x1eTEST_CASE_STATUS_RUNNING_ERROR\x10\x02\x12\"\n" +
+	"\x1eTEST_CASE_STATUS_OUT_OF_MEMORY\x10\x03\x12\x1c\n" +
+	"\x18TEST_CASE_STATUS_TIMEOUT\x10\x04\x12$\n" +
+	" TEST_CASE_STATUS_OUTPUT_OVERFLOW\x10\x05*\xbf\x01\n" +
+	"\x10AttemptEventType\x12\x1d\n" +
+	"\x19ATTEMPT_EVENT_TYPE_QUEUED\x10\x00\x12 \n" +
+	"\x1cATTEMPT_EVENT_TYPE_COMPILING\x10\x01\x12\x1f\n" +
+	"\x1bATTEMPT_EVENT_TYPE_COMPILED\x10\x02\x12#\n" +
+	"\x1fATTEMPT_EVENT_TYPE_TEST_STARTED\x10\x03\x12$\n" +
+	" ATTEMPT_EVENT_TYPE_TEST_FINISHED\x10\x042\xc9\x02\n" +
+	"\x06Runner\x12H\n" +
+	"\x03Run\x12!.rankode.runner.v1.AttemptRequest\x1a\x1c.rankode.runner.v1.RunUpdate0\x01\x12N\n" +
+	"\x06Submit\x12!.rankode.runner.v1.AttemptRequest\x1a!.rankode.runner.v1.SubmitResponse\x12M\n" +
+	"\x06Cancel\x12 .rankode.runner.v1.CancelRequest\x1a!.rankode.runner.v1.CancelResponse\x12V\n" +
+	"\fStreamEvents\x12&.rankode.runner.v1.StreamEventsRequest\x1a\x1c.rankode.runner.v1.RunUpdate0\x01B2Z0github.com/cutekitek/rankode-runner/pkg/runnerpbb\x06proto3"
+
+var (
+	file_runner_proto_rawDescOnce sync.Once
+	file_runner_proto_rawDescData []byte
+)
+
+func file_runner_proto_rawDescGZIP() []byte {
+	file_runner_proto_rawDescOnce.Do(func() {
+		file_runner_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_runner_proto_rawDesc), len(file_runner_proto_rawDesc)))
+	})
+	return file_runner_proto_rawDescData
+}
+
+var file_runner_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_runner_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_runner_proto_goTypes = []any{
+	(AttemptStatus)(0),          // 0: rankode.runner.v1.AttemptStatus
+	(TestCaseStatus)(0),         // 1: rankode.runner.v1.TestCaseStatus
+	(AttemptEventType)(0),       // 2: rankode.runner.v1.AttemptEventType
+	(*TestCase)(nil),            // 3: rankode.runner.v1.TestCase
+	(*SourceFile)(nil),          // 4: rankode.runner.v1.SourceFile
+	(*Attachment)(nil),          // 5: rankode.runner.v1.Attachment
+	(*AttemptRequest)(nil),      // 6: rankode.runner.v1.AttemptRequest
+	(*TestStatus)(nil),          // 7: rankode.runner.v1.TestStatus
+	(*AttemptResponse)(nil),     // 8: rankode.runner.v1.AttemptResponse
+	(*GroupResult)(nil),         // 9: rankode.runner.v1.GroupResult
+	(*AttemptEvent)(nil),        // 10: rankode.runner.v1.AttemptEvent
+	(*RunUpdate)(nil),           // 11: rankode.runner.v1.RunUpdate
+	(*SubmitResponse)(nil),      // 12: rankode.runner.v1.SubmitResponse
+	(*CancelRequest)(nil),       // 13: rankode.runner.v1.CancelRequest
+	(*CancelResponse)(nil),      // 14: rankode.runner.v1.CancelResponse
+	(*StreamEventsRequest)(nil), // 15: rankode.runner.v1.StreamEventsRequest
+}
+var file_runner_proto_depIdxs = []int32{
+	3,  // 0: rankode.runner.v1.AttemptRequest.test_cases:type_name -> rankode.runner.v1.TestCase
+	4,  // 1: rankode.runner.v1.AttemptRequest.files:type_name -> rankode.runner.v1.SourceFile
+	5,  // 2: rankode.runner.v1.AttemptRequest.attachments:type_name -> rankode.runner.v1.Attachment
+	1,  // 3: rankode.runner.v1.TestStatus.status:type_name -> rankode.runner.v1.TestCaseStatus
+	0,  // 4: rankode.runner.v1.AttemptResponse.status:type_name -> rankode.runner.v1.AttemptStatus
+	7,  // 5: rankode.runner.v1.AttemptResponse.tests:type_name -> rankode.runner.v1.TestStatus
+	9,  // 6: rankode.runner.v1.AttemptResponse.groups:type_name -> rankode.runner.v1.GroupResult
+	2,  // 7: rankode.runner.v1.AttemptEvent.type:type_name -> rankode.runner.v1.AttemptEventType
+	1,  // 8: rankode.runner.v1.AttemptEvent.status:type_name -> rankode.runner.v1.TestCaseStatus
+	10, // 9: rankode.runner.v1.RunUpdate.event:type_name -> rankode.runner.v1.AttemptEvent
+	8,  // 10: rankode.runner.v1.RunUpdate.result:type_name -> rankode.runner.v1.AttemptResponse
+	6,  // 11: rankode.runner.v1.Runner.Run:input_type -> rankode.runner.v1.AttemptRequest
+	6,  // 12: rankode.runner.v1.Runner.Submit:input_type -> rankode.runner.v1.AttemptRequest
+	13, // 13: rankode.runner.v1.Runner.Cancel:input_type -> rankode.runner.v1.CancelRequest
+	15, // 14: rankode.runner.v1.Runner.StreamEvents:input_type -> rankode.runner.v1.StreamEventsRequest
+	11, // 15: rankode.runner.v1.Runner.Run:output_type -> rankode.runner.v1.RunUpdate
+	12, // 16: rankode.runner.v1.Runner.Submit:output_type -> rankode.runner.v1.SubmitResponse
+	14, // 17: rankode.runner.v1.Runner.Cancel:output_type -> rankode.runner.v1.CancelResponse
+	11, // 18: rankode.runner.v1.Runner.StreamEvents:output_type -> rankode.runner.v1.RunUpdate
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_runner_proto_init() }
+func file_runner_proto_init() {
+	if File_runner_proto != nil {
+		return
+	}
+	file_runner_proto_msgTypes[0].OneofWrappers = []any{}
+	file_runner_proto_msgTypes[4].OneofWrappers = []any{}
+	file_runner_proto_msgTypes[8].OneofWrappers = []any{
+		(*RunUpdate_Event)(nil),
+		(*RunUpdate_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_runner_proto_rawDesc), len(file_runner_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_runner_proto_goTypes,
+		DependencyIndexes: file_runner_proto_depIdxs,
+		EnumInfos:         file_runner_proto_enumTypes,
+		MessageInfos:      file_runner_proto_msgTypes,
+	}.Build()
+	File_runner_proto = out.File
+	file_runner_proto_goTypes = nil
+	file_runner_proto_depIdxs = nil
+}
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: runner.proto
+
+package runnerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Runner_Run_FullMethodName          = "/rankode.runner.v1.Runner/Run"
+	Runner_Submit_FullMethodName       = "/rankode.runner.v1.Runner/Submit"
+	Runner_Cancel_FullMethodName       = "/rankode.runner.v1.Runner/Cancel"
+	Runner_StreamEvents_FullMethodName = "/rankode.runner.v1.Runner/StreamEvents"
+)
+
+// RunnerClient is the client API for Runner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Runner judges code attempts. Messages mirror JSON messages of the RabbitMQ transport.
+type RunnerClient interface {
+	// Runs the attempt and streams progress events. The last update carries the result.
+	// The attempt is cancelled if the client goes away.
+	Run(ctx context.Context, in *AttemptRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunUpdate], error)
+	// Queues the attempt and returns immediately. Use StreamEvents to get the result.
+	Submit(ctx context.Context, in *AttemptRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// Stops a running or queued attempt, it finishes with CANCELLED status.
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
+	// Streams events of an attempt started by Run or Submit from the beginning.
+	// The last update carries the result. Results are kept for an hour.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunUpdate], error)
+}
+
+type runnerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRunnerClient(cc grpc.ClientConnInterface) RunnerClient {
+	return &runnerClient{cc}
+}
+
+func (c *runnerClient) Run(ctx context.Context, in *AttemptRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Runner_ServiceDesc.Streams[0], Runner_Run_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AttemptRequest, RunUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Runner_RunClient = grpc.ServerStreamingClient[RunUpdate]
+
+func (c *runnerClient) Submit(ctx context.Context, in *AttemptRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, Runner_Submit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelResponse)
+	err := c.cc.Invoke(ctx, Runner_Cancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Runner_ServiceDesc.Streams[1], Runner_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, RunUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Runner_StreamEventsClient = grpc.ServerStreamingClient[RunUpdate]
+
+// RunnerServer is the server API for Runner service.
+// All implementations must embed UnimplementedRunnerServer
+// for forward compatibility.
+//
+// Runner judges code attempts. Messages mirror JSON messages of the RabbitMQ transport.
+type RunnerServer interface {
+	// Runs the attempt and streams progress events. The last update carries the result.
+	// The attempt is cancelled if the client goes away.
+	Run(*AttemptRequest, grpc.ServerStreamingServer[RunUpdate]) error
+	// Queues the attempt and returns immediately. Use StreamEvents to get the result.
+	Submit(context.Context, *AttemptRequest) (*SubmitResponse, error)
+	// Stops a running or queued attempt, it finishes with CANCELLED status.
+	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
+	// Streams events of an attempt started by Run or Submit from the beginning.
+	// The last update carries the result. Results are kept for an hour.
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[RunUpdate]) error
+	mustEmbedUnimplementedRunnerServer()
+}
+
+// UnimplementedRunnerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRunnerServer struct{}
+
+func (UnimplementedRunnerServer) Run(*AttemptRequest, grpc.ServerStreamingServer[RunUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method Run not implemented")
+}
+func (UnimplementedRunnerServer) Submit(context.Context, *AttemptRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (UnimplementedRunnerServer) Cancel(context.Context, *CancelRequest) (*CancelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedRunnerServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[RunUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedRunnerServer) mustEmbedUnimplementedRunnerServer() {}
+func (UnimplementedRunnerServer) testEmbeddedByValue()                {}
+
+// UnsafeRunnerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RunnerServer will
+// result in compilation errors.
+type UnsafeRunnerServer interface {
+	mustEmbedUnimplementedRunnerServer()
+}
+
+func RegisterRunnerServer(s grpc.ServiceRegistrar, srv RunnerServer) {
+	// If the following call pancis, it indicates UnimplementedRunnerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Runner_ServiceDesc, srv)
+}
+
+func _Runner_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttemptRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RunnerServer).Run(m, &grpc.GenericServerStream[AttemptRequest, RunUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Runner_RunServer = grpc.ServerStreamingServer[RunUpdate]
+
+func _Runner_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttemptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Runner_Submit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).Submit(ctx, req.(*AttemptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Runner_Cancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Runner_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RunnerServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, RunUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Runner_StreamEventsServer = grpc.ServerStreamingServer[RunUpdate]
+
+// Runner_ServiceDesc is the grpc.ServiceDesc for Runner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Runner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rankode.runner.v1.Runner",
+	HandlerType: (*RunnerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Submit",
+			Handler:    _Runner_Submit_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Runner_Cancel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			Handler:       _Runner_Run_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Runner_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "runner.proto",
+}